@@ -0,0 +1,29 @@
+package collector
+
+// sample the idle containers only every idleEvery polls, set by -adaptive
+// and -idle-every. The deltas of an idle container then cover the time since
+// its last sample.
+var adaptive bool
+var idleEvery = 5
+
+// the container used no CPU during its last sample
+func (this *Container) idle() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.current != nil && !noDelta && !this.WarmingUp() &&
+		this.current.CpuStats.CpuUsage.TotalUsage == 0
+}
+
+// the container is to be sampled this poll
+func (this *Container) dueForUpdate() bool {
+	if !adaptive || !this.idle() {
+		this.skipped = 0
+		return true
+	}
+	this.skipped++
+	if this.skipped >= idleEvery {
+		this.skipped = 0
+		return true
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// export the histogram of the container ages per poll, set by -age-histogram
+var ageHistogram bool
+
+// the number of containers per age bucket, the age is counted from when the
+// collector first saw the container
+type AgeHistogram struct {
+	Under1m    int `json:"lt_1m"`
+	Under10m   int `json:"1m_10m"`
+	Under1h    int `json:"10m_1h"`
+	Over1h     int `json:"gt_1h"`
+	Containers int `json:"containers"`
+	// the sum of the ages, the sum of the Prometheus histogram
+	Seconds float64 `json:"age_seconds_sum"`
+}
+
+func (this *AgeHistogram) Add(age time.Duration) {
+	this.Containers++
+	this.Seconds += age.Seconds()
+	switch {
+	case age < time.Minute:
+		this.Under1m++
+	case age < 10*time.Minute:
+		this.Under10m++
+	case age < time.Hour:
+		this.Under1h++
+	default:
+		this.Over1h++
+	}
+}
+
+func (this *AgeHistogram) Print() {
+	fmt.Printf("ages%s containers=%d lt_1m=%d 1m_10m=%d 10m_1h=%d gt_1h=%d\n", collectorLabel(), this.Containers,
+		this.Under1m, this.Under10m, this.Under1h, this.Over1h)
+}
+
+// the ages of the last poll, read by /metrics and the JSON output
+var pollAges struct {
+	sync.Mutex
+	ages AgeHistogram
+}
+
+func setPollAges(ages AgeHistogram) {
+	pollAges.Lock()
+	pollAges.ages = ages
+	pollAges.Unlock()
+}
+
+func lastPollAges() AgeHistogram {
+	pollAges.Lock()
+	defer pollAges.Unlock()
+	return pollAges.ages
+}
+
+// the bucket bounds of AgeHistogram in seconds
+var ageBuckets = []float64{60, 600, 3600}
+
+// the ages of the last poll as a host level histogram, with -age-histogram
+type ageCollector struct {
+	desc *prometheus.Desc
+}
+
+var containerAges = &ageCollector{desc: prometheus.NewDesc("docker_container_ages_seconds",
+	"Ages of the containers of the last poll since the collector first saw them or their restart in seconds.", nil, nil)}
+
+func (this *ageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- this.desc
+}
+
+func (this *ageCollector) Collect(ch chan<- prometheus.Metric) {
+	if !ageHistogram {
+		return
+	}
+	ages := lastPollAges()
+	// the buckets are cumulative
+	counts := []int{ages.Under1m, ages.Under1m + ages.Under10m, ages.Under1m + ages.Under10m + ages.Under1h}
+	buckets := make(map[float64]uint64, len(ageBuckets))
+	for i, le := range ageBuckets {
+		buckets[le] = uint64(counts[i])
+	}
+	ch <- prometheus.MustNewConstHistogram(this.desc, uint64(ages.Containers), ages.Seconds, buckets)
+}
+
+// the inode of the first cgroup dir of the container. The dir of a restarted
+// container is created anew, so the inode changes even if the id doesn't.
+func cgroupDirIno(cpath map[string]string) (ino uint64, ok bool) {
+	names := make([]string, 0, len(cpath))
+	for name := range cpath {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fi, err := files.Stat(cpath[name])
+		if err != nil {
+			continue
+		}
+		if st, isStat := fi.Sys().(*syscall.Stat_t); isStat {
+			return st.Ino, true
+		}
+	}
+	return 0, false
+}
+
+// the container restarted since the previous sample, its cgroup dir was
+// recreated or its CPU usage went backwards
+func (this *Container) UpdateAge(stat *cgroups.Stats) (restarted bool) {
+	if ino, ok := cgroupDirIno(this.cgroupPath); ok {
+		if this.cgroupIno != 0 && ino != this.cgroupIno {
+			this.logger.Info("the cgroup dir was recreated, the container restarted")
+			restarted = true
+		}
+		this.cgroupIno = ino
+	}
+	if !restarted && this.previous != nil && stat.CpuStats.CpuUsage.TotalUsage < this.previous.CpuStats.CpuUsage.TotalUsage {
+		this.logger.Info("the CPU usage was reset, the container restarted")
+		restarted = true
+	}
+	if restarted {
+		this.restart()
+	}
+	return
+}
+
+// the counters started over, the sample becomes the new baseline and the
+// deltas wait for the next one like on a new container
+func (this *Container) restart() {
+	this.restarts++
+	this.firstSeen = this.sampled
+	this.previous = nil
+	this.previousSampled = time.Time{}
+	this.cpuSampled = time.Time{}
+	this.samples = 0
+	this.smoothed = CpuSmoothing{}
+}
+
+// the time since the collector first saw the container or its restart
+func (this *Container) Age() time.Duration {
+	return time.Since(this.firstSeen)
+}
@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAgeHistogramExport(t *testing.T) {
+	saved := ageHistogram
+	ageHistogram = true
+	defer func() {
+		ageHistogram = saved
+		setPollAges(AgeHistogram{})
+	}()
+
+	var ages AgeHistogram
+	for _, age := range []time.Duration{30 * time.Second, 5 * time.Minute, 2 * time.Hour} {
+		ages.Add(age)
+	}
+	setPollAges(ages)
+
+	expected := `
+# HELP docker_container_ages_seconds Ages of the containers of the last poll since the collector first saw them or their restart in seconds.
+# TYPE docker_container_ages_seconds histogram
+docker_container_ages_seconds_bucket{le="60"} 1
+docker_container_ages_seconds_bucket{le="600"} 2
+docker_container_ages_seconds_bucket{le="3600"} 2
+docker_container_ages_seconds_bucket{le="+Inf"} 3
+docker_container_ages_seconds_sum 7530
+docker_container_ages_seconds_count 3
+`
+	if err := testutil.CollectAndCompare(containerAges, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeHostStats(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var host HostStats
+	if err := json.Unmarshal(buf.Bytes(), &host); err != nil {
+		t.Fatal(err)
+	}
+	if host.HostSchemaVersion != hostSchemaVersion || host.Ages != ages {
+		t.Errorf("host stats %+v, expected the ages %+v", host, ages)
+	}
+
+	ageHistogram = false
+	if n := testutil.CollectAndCount(containerAges); n != 0 {
+		t.Errorf("%d age series without -age-histogram", n)
+	}
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the block I/O of the container, the totals are cumulative over all the
+// devices and the rates are per second since the previous sample
+type BlkioInfo struct {
+	ReadBytes      uint64
+	WriteBytes     uint64
+	ReadBytesRate  float64
+	WriteBytesRate float64
+	sampled        bool
+}
+
+// sum the entries of all the devices by operation, the kernel reports one
+// line per device and operation
+func sumBlkio(entries []cgroups.BlkioStatEntry) (read, write uint64) {
+	for _, e := range entries {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += e.Value
+		case "write":
+			write += e.Value
+		}
+	}
+	return
+}
+
+func (this *Container) UpdateBlkio(stat cgroups.BlkioStats) {
+	read, write := sumBlkio(stat.IoServiceBytesRecursive)
+	previous := this.blkio
+	this.blkio = BlkioInfo{ReadBytes: read, WriteBytes: write, sampled: true}
+	if !previous.sampled || this.previousSampled.IsZero() {
+		return
+	}
+	rates := counterRates(counters{"read": read, "write": write},
+		counters{"read": previous.ReadBytes, "write": previous.WriteBytes}, this.sampled.Sub(this.previousSampled))
+	this.blkio.ReadBytesRate = rates["read"]
+	this.blkio.WriteBytesRate = rates["write"]
+}
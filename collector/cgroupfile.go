@@ -0,0 +1,213 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// read a single value cgroup file like memory.limit_in_bytes, without the
+// trailing newline the kernel adds
+func readCgroupString(file string) (value string, err error) {
+	var out []byte
+	out, err = files.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// read a single integer cgroup file like pids.current
+func readCgroupUint(file string) (value uint64, err error) {
+	var s string
+	s, err = readCgroupString(file)
+	if err != nil {
+		return
+	}
+	value, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %s", file, err.Error())
+	}
+	return
+}
+
+// read a cgroup file of "key value" lines like pids.events or memory.events
+func readKeyValues(file string) (values map[string]uint64, err error) {
+	var out []byte
+	out, err = files.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	values = make(map[string]uint64)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("failed to parse %s entry %s", file, line)
+		}
+		var v uint64
+		v, err = strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s entry %s: %s", file, line, err.Error())
+		}
+		values[fields[0]] = v
+	}
+	return
+}
+
+// the "max" counter of pids.events, the number of times a fork failed because
+// the pids limit was hit. The file does not exist on older kernels.
+func readPidsEvents(pidsPath string) (max uint64, ok bool) {
+	if pidsPath == "" {
+		return 0, false
+	}
+	values, err := readKeyValues(path.Join(pidsPath, "pids.events"))
+	if err != nil {
+		return 0, false
+	}
+	max, ok = values["max"]
+	return
+}
+
+// the first pid listed in the cgroup.procs of the cgroup
+func readFirstPid(cgroupPath string) (pid int, err error) {
+	var out []byte
+	out, err = files.ReadFile(path.Join(cgroupPath, "cgroup.procs"))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("no process in %s", cgroupPath)
+	}
+	return strconv.Atoi(fields[0])
+}
+
+// the names of the scheduling policies, see sched(7)
+var schedPolicies = map[int]string{
+	0: "normal",
+	1: "fifo",
+	2: "rr",
+	3: "batch",
+	5: "idle",
+	6: "deadline",
+}
+
+// read the scheduling policy and the nice value of the process from the
+// /proc/[pid]/stat, field (41) policy and field (19) nice
+// Please see more on http://man7.org/linux/man-pages/man5/proc.5.html
+func readSched(pid int) (policy string, nice int, err error) {
+	var out []byte
+	out, err = files.ReadFile(procPath(strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return
+	}
+	// the comm (2) may contain spaces, so start after its closing parenthesis
+	stat := string(out)
+	fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+	// fields[0] is field (3)
+	if len(fields) < 41-3+1 {
+		return "", 0, fmt.Errorf("failed to parse /proc/%d/stat", pid)
+	}
+	nice, err = strconv.Atoi(fields[19-3])
+	if err != nil {
+		return
+	}
+	var p int
+	p, err = strconv.Atoi(fields[41-3])
+	if err != nil {
+		return
+	}
+	policy, ok := schedPolicies[p]
+	if !ok {
+		policy = strconv.Itoa(p)
+	}
+	return
+}
+
+// the RDMA resources used on one device, from rdma.current
+type RdmaUsage struct {
+	HcaHandle uint64 `json:"hca_handle"`
+	HcaObject uint64 `json:"hca_object"`
+}
+
+// read the rdma.current file, it contains lines of the form:
+//
+// mlx4_0 hca_handle=2 hca_object=2000
+func readRdma(rdmaPath string) (usage map[string]RdmaUsage, err error) {
+	var out []byte
+	out, err = files.ReadFile(path.Join(rdmaPath, "rdma.current"))
+	if err != nil {
+		return nil, err
+	}
+	usage = make(map[string]RdmaUsage)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var dev RdmaUsage
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("failed to parse rdma.current entry %s", line)
+			}
+			var v uint64
+			v, err = strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse rdma.current entry %s: %s", line, err.Error())
+			}
+			switch kv[0] {
+			case "hca_handle":
+				dev.HcaHandle = v
+			case "hca_object":
+				dev.HcaObject = v
+			}
+		}
+		usage[fields[0]] = dev
+	}
+	return
+}
+
+// the memory limit of one cgroup, memory.limit_in_bytes on v1 and memory.max
+// on v2 where "max" means no limit
+func readMemoryLimit(dir string) (limit uint64, err error) {
+	limit, err = readCgroupUint(path.Join(dir, "memory.limit_in_bytes"))
+	if err == nil {
+		return
+	}
+	var v string
+	v, err = readCgroupString(path.Join(dir, "memory.max"))
+	if err != nil {
+		return
+	}
+	if v == "max" {
+		return math.MaxUint64, nil
+	}
+	limit, err = strconv.ParseUint(v, 10, 64)
+	return
+}
+
+// the limit applying to the cgroup is the smallest along its path up to the
+// mount point, e.g. a pod or slice limit caps all the containers below it
+func readEffectiveMemoryLimit(root string, cgroupPath string) (limit uint64, ok bool) {
+	limit = math.MaxUint64
+	for dir := cgroupPath; ; dir = path.Dir(dir) {
+		if v, err := readMemoryLimit(dir); err == nil {
+			ok = true
+			if v < limit {
+				limit = v
+			}
+		}
+		if len(dir) <= len(root) || dir == "/" {
+			break
+		}
+	}
+	// 0 when no dir along the path has a limit
+	limit = normalizeMemoryLimit(limit)
+	return
+}
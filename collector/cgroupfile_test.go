@@ -0,0 +1,31 @@
+package collector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReadCgroupFilesTrailingNewline(t *testing.T) {
+	useFixtures(t, map[string]string{
+		"/v1/memory.limit_in_bytes": "12345\n",
+		"/v2/memory.max":            "max\n",
+		"/v2-limited/memory.max":    "12345\n",
+		"/pids.current":             "12345\n",
+		"/pids.max":                 "max\n",
+	})
+	if v, err := readCgroupUint("/pids.current"); err != nil || v != 12345 {
+		t.Errorf("readCgroupUint of 12345\\n = %d, %v, want 12345", v, err)
+	}
+	if _, err := readCgroupUint("/pids.max"); err == nil {
+		t.Error("readCgroupUint parsed max\\n")
+	}
+	for dir, want := range map[string]uint64{
+		"/v1":         12345,
+		"/v2":         math.MaxUint64,
+		"/v2-limited": 12345,
+	} {
+		if v, err := readMemoryLimit(dir); err != nil || v != want {
+			t.Errorf("readMemoryLimit(%s) = %d, %v, want %d", dir, v, err, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the host only has the cgroup v2 unified hierarchy mounted, all the
+// controllers share one directory per cgroup
+func isCgroupV2(cpath map[string]string) bool {
+	_, ok := cpath[unifiedSubsystem]
+	return ok && len(cpath) == 1
+}
+
+// the directory of the controller's files. On v2 every controller is in the
+// unified directory.
+func (this *Container) controllerPath(name string) (p string, ok bool) {
+	if p, ok = this.cgroupPath[name]; ok {
+		return
+	}
+	if isCgroupV2(this.cgroupPath) {
+		return this.cgroupPath[unifiedSubsystem], true
+	}
+	return "", false
+}
+
+// the mount point the controller's directory is below
+func (this *Container) controllerRoot(name string) string {
+	if root, ok := this.cgroupRoot[name]; ok {
+		return root
+	}
+	return this.cgroupRoot[unifiedSubsystem]
+}
+
+// read a v2 value which is "max" when there is no limit
+func readCgroupMax(file string) (value uint64, err error) {
+	var s string
+	s, err = readCgroupString(file)
+	if err != nil {
+		return
+	}
+	if s == "max" {
+		return math.MaxUint64, nil
+	}
+	value, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %s", file, err.Error())
+	}
+	return
+}
+
+// read the stats from the files of a v2 cgroup, fs.Manager only knows v1
+func getStatsV2(dir string) (stat *cgroups.Stats, status string, err error) {
+	stat = cgroups.NewStats()
+	failed := &statErrors{}
+	readers := map[string]func(string, *cgroups.Stats) error{
+		"cpu":    getCpuStatsV2,
+		"memory": getMemoryStatsV2,
+		"pids":   getPidsStatsV2,
+	}
+	for name := range readers {
+		if !collectSubsystem(name) {
+			delete(readers, name)
+		}
+	}
+	if len(readers) == 0 {
+		return stat, StatusOk, nil
+	}
+	for name, read := range readers {
+		if err := read(dir, stat); err != nil {
+			countFileError(err)
+			failed.add(name, err)
+		}
+	}
+	if len(failed.failed) == 0 {
+		return stat, StatusOk, nil
+	}
+	if len(failed.failed) == len(readers) {
+		return nil, StatusError, failed
+	}
+	return stat, StatusPartial, failed
+}
+
+// cpu.stat has the times in microseconds, the v1 stats are in nanoseconds
+func getCpuStatsV2(dir string, stat *cgroups.Stats) error {
+	values, err := readKeyValues(path.Join(dir, "cpu.stat"))
+	if err != nil {
+		return err
+	}
+	stat.CpuStats.CpuUsage.TotalUsage = values["usage_usec"] * 1000
+	stat.CpuStats.CpuUsage.UsageInUsermode = values["user_usec"] * 1000
+	stat.CpuStats.CpuUsage.UsageInKernelmode = values["system_usec"] * 1000
+	stat.CpuStats.ThrottlingData.Periods = values["nr_periods"]
+	stat.CpuStats.ThrottlingData.ThrottledPeriods = values["nr_throttled"]
+	stat.CpuStats.ThrottlingData.ThrottledTime = values["throttled_usec"] * 1000
+	return nil
+}
+
+func getMemoryStatsV2(dir string, stat *cgroups.Stats) (err error) {
+	stat.MemoryStats.Usage.Usage, err = readCgroupUint(path.Join(dir, "memory.current"))
+	if err != nil {
+		return
+	}
+	stat.MemoryStats.Usage.Limit, err = readCgroupMax(path.Join(dir, "memory.max"))
+	if err != nil {
+		return
+	}
+	stat.MemoryStats.Stats, err = readKeyValues(path.Join(dir, "memory.stat"))
+	if err != nil {
+		return
+	}
+	stat.MemoryStats.Cache = stat.MemoryStats.Stats["file"]
+	// the swap files only exist when swap accounting is enabled
+	if swap, err := readCgroupUint(path.Join(dir, "memory.swap.current")); err == nil {
+		stat.MemoryStats.SwapUsage.Usage = swap
+	}
+	if swap, err := readCgroupMax(path.Join(dir, "memory.swap.max")); err == nil {
+		stat.MemoryStats.SwapUsage.Limit = swap
+	}
+	return nil
+}
+
+func getPidsStatsV2(dir string, stat *cgroups.Stats) (err error) {
+	stat.PidsStats.Current, err = readCgroupUint(path.Join(dir, "pids.current"))
+	if err != nil {
+		return
+	}
+	stat.PidsStats.Limit, err = readCgroupMax(path.Join(dir, "pids.max"))
+	return
+}
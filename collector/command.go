@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"strconv"
+	"strings"
+)
+
+// label the stats with the command of the container's first process, set by -command
+var commandInfo bool
+
+// the cmdline is cut to this many bytes, the label is for humans
+const commandMaxLength = 80
+
+// the process the command was read from and its command
+type CommandInfo struct {
+	pid     int
+	Comm    string
+	Cmdline string
+}
+
+// read the comm and the NUL separated cmdline of the process
+func readCommand(pid int) (comm string, cmdline string, err error) {
+	comm, err = readCgroupString(procPath(strconv.Itoa(pid), "comm"))
+	if err != nil {
+		return
+	}
+	var out []byte
+	out, err = files.ReadFile(procPath(strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return
+	}
+	cmdline = strings.TrimSpace(strings.Replace(string(out), "\x00", " ", -1))
+	if len(cmdline) > commandMaxLength {
+		cmdline = cmdline[:commandMaxLength]
+	}
+	return
+}
+
+// the command is only read again when the first process changed. The process
+// may exit between reading cgroup.procs and its comm, the command is
+// "unknown" then until the next poll.
+func (this *Container) UpdateCommand() {
+	for _, p := range this.cgroupPath {
+		pid, err := readFirstPid(p)
+		if err != nil {
+			continue
+		}
+		if pid == this.command.pid && this.command.Comm != "unknown" {
+			return
+		}
+		comm, cmdline, err := readCommand(pid)
+		if err != nil {
+			this.command = CommandInfo{pid: pid, Comm: "unknown"}
+			return
+		}
+		this.command = CommandInfo{pid: pid, Comm: comm, Cmdline: cmdline}
+		return
+	}
+	this.command = CommandInfo{}
+}
@@ -0,0 +1,174 @@
+package collector
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// the file with the options, set by -config. The flags on the command line
+// win over the file. A SIGHUP reloads it, see reloadableOptions.
+var configFile string
+
+// read a config file of the form:
+//
+//	# a comment
+//	interval: 10s
+//	output: json
+//	cgroup-parent:
+//	  - docker
+//	  - system.slice
+//
+// The keys are the flag names and the lists are joined with commas like the
+// list flags take them. It's the flat subset of YAML the options need, the
+// values of the rest of YAML like a flow list [a, b] or a block scalar are
+// errors rather than read as a string, they have to be quoted for that.
+func parseConfig(data string) (values map[string]string, lines map[string]int, err error) {
+	values = make(map[string]string)
+	lines = make(map[string]int)
+	var list string
+	for i, line := range strings.Split(data, "\n") {
+		line = stripComment(line)
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") {
+			if list == "" {
+				return nil, nil, fmt.Errorf("line %d: list item without a key", i+1)
+			}
+			item := strings.TrimSpace(trimmed[2:])
+			if err = checkPlain(item); err != nil {
+				return nil, nil, fmt.Errorf("line %d: %s", i+1, err.Error())
+			}
+			item = unquote(item)
+			if values[list] != "" {
+				item = values[list] + "," + item
+			}
+			values[list] = item
+			continue
+		}
+		kv := strings.SplitN(trimmed, ":", 2)
+		if len(kv) != 2 || line != strings.TrimLeft(line, " \t") {
+			return nil, nil, fmt.Errorf("line %d: expected \"key: value\", got %q", i+1, trimmed)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if err = checkPlain(value); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %s", i+1, err.Error())
+		}
+		if _, ok := lines[key]; ok {
+			return nil, nil, fmt.Errorf("line %d: duplicate key %s", i+1, key)
+		}
+		lines[key] = i + 1
+		list = ""
+		if value == "" {
+			// the items of a block list follow
+			list = key
+		}
+		values[key] = unquote(value)
+	}
+	return
+}
+
+// cut the comment off the line, a # at the start or after a blank which
+// isn't inside a quoted value like "#ff0000". Like in YAML only a quote
+// starting the value or list item quotes, the one of it's doesn't.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			// the escaped char can't end the quote
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && startsValue(line[:i]):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// the text before a value, a key with its colon or a list dash
+func startsValue(before string) bool {
+	before = strings.TrimRight(before, " \t")
+	return before == "" || strings.HasSuffix(before, ":") || strings.HasSuffix(before, "-")
+}
+
+// the YAML indicators starting a flow collection, a block scalar, an anchor
+// or an alias, which parseConfig doesn't read
+const yamlIndicators = "[{|>&*"
+
+func checkPlain(value string) error {
+	if value != "" && strings.IndexByte(yamlIndicators, value[0]) >= 0 {
+		return fmt.Errorf("unsupported YAML value %s, quote it for a string", value)
+	}
+	return nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '"' {
+			if u, err := strconv.Unquote(s); err == nil {
+				return u
+			}
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// the options of the last loaded config file and the flags given on the
+// command line, which a reload doesn't touch either
+var loadedConfig struct {
+	values      map[string]string
+	commandLine map[string]bool
+}
+
+// read and check the config file, the keys must be flags of fs
+func readConfig(fs *flag.FlagSet, file string) (values map[string]string, lines map[string]int, err error) {
+	var out []byte
+	out, err = files.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %s", file, err.Error())
+	}
+	values, lines, err = parseConfig(string(out))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %s", file, err.Error())
+	}
+	for key := range values {
+		if key == "config" {
+			return nil, nil, fmt.Errorf("%s:%d: config can't be set in the config file", file, lines[key])
+		}
+		if fs.Lookup(key) == nil {
+			return nil, nil, fmt.Errorf("%s:%d: unknown option %s", file, lines[key], key)
+		}
+	}
+	return
+}
+
+// set the flags from the config file, except the ones given on the command line
+func loadConfig(fs *flag.FlagSet, file string) (err error) {
+	values, lines, err := readConfig(fs, file)
+	if err != nil {
+		return
+	}
+	loadedConfig.commandLine = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { loadedConfig.commandLine[f.Name] = true })
+	for key, value := range values {
+		if loadedConfig.commandLine[key] {
+			continue
+		}
+		if err = fs.Set(key, value); err != nil {
+			return fmt.Errorf("%s:%d: invalid %s %q: %s", file, lines[key], key, value, err.Error())
+		}
+	}
+	loadedConfig.values = values
+	return nil
+}
@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	values, lines, err := parseConfig(`# the collector
+---
+interval: 10s # every 10s
+output: json
+collector-id: "web #1" # quoted
+name-source: 'label:team#a'
+influx-url: http://influx/write?db=docker#frag
+label-filter: it's # not a quote
+cgroup-parent:
+  - docker # cgroupfs
+  - "system.slice #2"
+`)
+	if err != nil {
+		t.Fatalf("parseConfig: %s", err)
+	}
+	want := map[string]string{
+		"interval":      "10s",
+		"output":        "json",
+		"collector-id":  "web #1",
+		"name-source":   "label:team#a",
+		"influx-url":    "http://influx/write?db=docker#frag",
+		"label-filter":  "it's",
+		"cgroup-parent": "docker,system.slice #2",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %v, want %v", values, want)
+	}
+	if lines["interval"] != 3 || lines["cgroup-parent"] != 9 {
+		t.Errorf("lines = %v, want interval at 3 and cgroup-parent at 9", lines)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	for _, data := range []string{
+		"- docker\n",
+		"interval\n",
+		"interval: 1s\ninterval: 2s\n",
+		"  interval: 1s\n",
+	} {
+		if _, _, err := parseConfig(data); err == nil {
+			t.Errorf("parseConfig(%q) gave no error", data)
+		}
+	}
+}
+
+func TestParseConfigRejectsYAML(t *testing.T) {
+	for name, data := range map[string]string{
+		"flow list":      "filter: [a, b]\n",
+		"flow map":       "label-filter: {a: b}\n",
+		"literal scalar": "filter: |\n  a\n",
+		"folded scalar":  "filter: >\n  a\n",
+		"anchor":         "filter: &f a\n",
+		"alias":          "filter: *f\n",
+		"list item":      "cgroup-parent:\n  - [a, b]\n",
+	} {
+		if _, _, err := parseConfig(data); err == nil {
+			t.Errorf("%s: parseConfig(%q) gave no error", name, data)
+		}
+	}
+	// quoted it's the string
+	values, _, err := parseConfig("filter: \"[a, b]\"\n")
+	if err != nil || values["filter"] != "[a, b]" {
+		t.Errorf("parseConfig of a quoted [a, b] gave %q, %v", values["filter"], err)
+	}
+}
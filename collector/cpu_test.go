@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func cpuStats(total, kernel, user uint64, percpu ...uint64) cgroups.CpuStats {
+	return cgroups.CpuStats{CpuUsage: cgroups.CpuUsage{
+		TotalUsage:        total,
+		UsageInKernelmode: kernel,
+		UsageInUsermode:   user,
+		PercpuUsage:       percpu,
+	}}
+}
+
+func TestUpdateCpu(t *testing.T) {
+	tests := []struct {
+		name     string
+		noDelta  bool
+		previous *cgroups.CpuStats
+		stat     cgroups.CpuStats
+		want     cgroups.CpuUsage
+	}{
+		{
+			name: "warming up keeps the cumulative usage",
+			stat: cpuStats(1000, 400, 600, 500, 500),
+			want: cgroups.CpuUsage{TotalUsage: 1000, UsageInKernelmode: 400, UsageInUsermode: 600, PercpuUsage: []uint64{500, 500}},
+		},
+		{
+			name:     "delta since the previous sample",
+			previous: &cgroups.CpuStats{CpuUsage: cgroups.CpuUsage{TotalUsage: 1000, UsageInKernelmode: 400, UsageInUsermode: 600, PercpuUsage: []uint64{400, 600}}},
+			stat:     cpuStats(1500, 500, 1000, 600, 900),
+			want:     cgroups.CpuUsage{TotalUsage: 500, UsageInKernelmode: 100, UsageInUsermode: 400, PercpuUsage: []uint64{200, 300}},
+		},
+		{
+			name:     "a counter which went backwards gives 0",
+			previous: &cgroups.CpuStats{CpuUsage: cgroups.CpuUsage{TotalUsage: 1000, UsageInKernelmode: 400, UsageInUsermode: 600, PercpuUsage: []uint64{400, 600}}},
+			stat:     cpuStats(1200, 300, 900, 300, 900),
+			want:     cgroups.CpuUsage{TotalUsage: 200, UsageInKernelmode: 0, UsageInUsermode: 300, PercpuUsage: []uint64{0, 300}},
+		},
+		{
+			name:     "a hotplugged CPU starts at 0",
+			previous: &cgroups.CpuStats{CpuUsage: cgroups.CpuUsage{TotalUsage: 1000, PercpuUsage: []uint64{400, 600}}},
+			stat:     cpuStats(1600, 0, 0, 500, 700, 400),
+			want:     cgroups.CpuUsage{TotalUsage: 600, PercpuUsage: []uint64{100, 100, 0}},
+		},
+		{
+			name:     "an offlined CPU drops out",
+			previous: &cgroups.CpuStats{CpuUsage: cgroups.CpuUsage{TotalUsage: 1000, PercpuUsage: []uint64{400, 600}}},
+			stat:     cpuStats(1100, 0, 0, 1100),
+			want:     cgroups.CpuUsage{TotalUsage: 100, PercpuUsage: []uint64{700}},
+		},
+		{
+			name:     "-no-delta keeps the cumulative usage",
+			noDelta:  true,
+			previous: &cgroups.CpuStats{CpuUsage: cgroups.CpuUsage{TotalUsage: 1000, PercpuUsage: []uint64{400, 600}}},
+			stat:     cpuStats(1500, 0, 0, 600, 900),
+			want:     cgroups.CpuUsage{TotalUsage: 1500, PercpuUsage: []uint64{600, 900}},
+		},
+	}
+	defer func(saved bool) { noDelta = saved }(noDelta)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noDelta = tt.noDelta
+			container := &Container{logger: log.WithField("container_id", "test")}
+			if tt.previous != nil {
+				container.previous = &cgroups.Stats{CpuStats: *tt.previous}
+			}
+			stat := &cgroups.Stats{CpuStats: tt.stat}
+			container.current = copyCpuStats(stat)
+			container.UpdateCpu(stat.CpuStats)
+			if got := container.current.CpuStats.CpuUsage; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			// the sample UpdateCpu got is the next baseline and stays cumulative
+			if !reflect.DeepEqual(stat.CpuStats, tt.stat) {
+				t.Errorf("the cumulative sample changed to %+v", stat.CpuStats)
+			}
+		})
+	}
+}
+
+func TestUpdateCpuPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous uint64
+		usage    uint64
+		want     float64
+		clamped  bool
+	}{
+		{name: "half of the two CPUs", previous: 1e9, usage: 2e9, want: 50},
+		{name: "idle", previous: 1e9, usage: 1e9, want: 0},
+		{name: "above all of the CPUs is clamped", previous: 1e9, usage: 6e9, want: 200, clamped: true},
+		{name: "a usage going backwards is skipped", previous: 2e9, usage: 1e9, want: 0, clamped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			container := &Container{logger: log.WithField("container_id", "test")}
+			container.cpuCumulative, container.cpuSampled = tt.previous, now.Add(-time.Second)
+			container.previousSampled, container.sampled = now.Add(-time.Second), now
+			before := testutil.ToFloat64(collectorCpuPercentOutOfRange)
+			container.UpdateCpuPercent(tt.usage, 2)
+			if container.cpuPercent != tt.want {
+				t.Errorf("cpu percent = %f, want %f", container.cpuPercent, tt.want)
+			}
+			if counted := testutil.ToFloat64(collectorCpuPercentOutOfRange) - before; counted != 0 != tt.clamped {
+				t.Errorf("counted %f out of range, want clamped %t", counted, tt.clamped)
+			}
+			if container.cpuCumulative != tt.usage {
+				t.Errorf("the baseline is %d, want %d", container.cpuCumulative, tt.usage)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"runtime"
+)
+
+// the CPUs of the host which are online, sysfs is not namespaced so the
+// collector sees the host's list in a container too
+var onlineCpusFile = "/sys/devices/system/cpu/online"
+
+// the number of online host CPUs, from the online list, else the per CPU
+// usage the cgroup reports, else the CPUs the runtime sees
+func hostCpus(percpu int) int {
+	if s, err := readCgroupString(onlineCpusFile); err == nil {
+		if list, err := parseCpuList(s); err == nil && len(list) > 0 {
+			return len(list)
+		}
+	}
+	if percpu > 0 {
+		return percpu
+	}
+	return runtime.NumCPU()
+}
+
+// the denominator of the CPU percentage, the cpuset of the container when
+// it's restricted to fewer CPUs than the host has online
+func (this *Container) UpdateCpuCount(percpu int) {
+	this.hostCpus = hostCpus(percpu)
+	this.cpus = this.hostCpus
+	if n := len(this.cpuset.Cpus); n > 0 && n < this.cpus {
+		this.cpus = n
+	}
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the CPUs and memory nodes the container may use, read from the cpuset
+// controller every poll. The cgroup files have no useful mtime, a changed
+// cpuset doesn't touch it.
+type CpusetInfo struct {
+	Cpus []int
+	Mems []int
+	// the lists as the kernel writes them, like 0-3,8
+	cpus string
+	mems string
+}
+
+// parse a list like 0-3,8,10-11 into the numbers it lists
+func parseCpuList(s string) (list []int, err error) {
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			lo, hi = part[:i], part[i+1:]
+		}
+		var from, to int
+		if from, err = strconv.Atoi(lo); err != nil {
+			return nil, fmt.Errorf("failed to parse cpu list %q: %w", s, err)
+		}
+		if to, err = strconv.Atoi(hi); err != nil {
+			return nil, fmt.Errorf("failed to parse cpu list %q: %w", s, err)
+		}
+		if to < from {
+			return nil, fmt.Errorf("failed to parse cpu list %q: bad range %s", s, part)
+		}
+		for i := from; i <= to; i++ {
+			list = append(list, i)
+		}
+	}
+	return
+}
+
+// the cpuset files of the controller dir, v2 only has the effective lists
+// when the container set none
+func cpusetFiles(dir string, v2 bool) (cpus, mems string) {
+	if v2 {
+		return path.Join(dir, "cpuset.cpus.effective"), path.Join(dir, "cpuset.mems.effective")
+	}
+	return path.Join(dir, "cpuset.cpus"), path.Join(dir, "cpuset.mems")
+}
+
+func (this *Container) UpdateCpuset() {
+	dir, ok := this.controllerPath("cpuset")
+	if !ok {
+		return
+	}
+	cpusFile, memsFile := cpusetFiles(dir, isCgroupV2(this.cgroupPath))
+	var info CpusetInfo
+	var err error
+	info.cpus, err = readCgroupString(cpusFile)
+	if err == nil {
+		info.mems, err = readCgroupString(memsFile)
+	}
+	if err == nil {
+		info.Cpus, err = parseCpuList(info.cpus)
+	}
+	if err == nil {
+		info.Mems, err = parseCpuList(info.mems)
+	}
+	if err != nil {
+		this.logger.WithFields(log.Fields{
+			"subsystem": "cpuset",
+			"error":     err.Error(),
+		}).Debug("failed to read cpuset")
+		return
+	}
+	this.cpuset = info
+}
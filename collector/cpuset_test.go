@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUpdateCpusetRereads(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	host.dirs["cpuset"] = host.cgroupRoot + "/cpuset"
+	host.mkdir(host.containerDir("cpuset", id))
+	host.writeCgroup("cpuset", id, "cpuset.cpus", "0-1\n")
+	host.writeCgroup("cpuset", id, "cpuset.mems", "0\n")
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	container.cgroupPath["cpuset"] = host.containerDir("cpuset", id)
+	container.UpdateCpuset()
+	if want := []int{0, 1}; !reflect.DeepEqual(container.cpuset.Cpus, want) {
+		t.Fatalf("cpus = %v, want %v", container.cpuset.Cpus, want)
+	}
+	// kernfs keeps the mtime when the cpuset changes
+	file := host.containerDir("cpuset", id) + "/cpuset.cpus"
+	fi, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host.writeCgroup("cpuset", id, "cpuset.cpus", "2-3\n")
+	if err := os.Chtimes(file, time.Now(), fi.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	container.UpdateCpuset()
+	if want := []int{2, 3}; !reflect.DeepEqual(container.cpuset.Cpus, want) {
+		t.Errorf("cpus = %v after the change, want %v", container.cpuset.Cpus, want)
+	}
+}
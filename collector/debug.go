@@ -0,0 +1,103 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// print the subsystems of /proc/cgroups as a table, for -list-subsystems
+func listSubsystems(w io.Writer) (err error) {
+	var cgroupDict map[string]CgroupsInfo
+	cgroupDict, err = getCgroups()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", procPath("cgroups"), err.Error())
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SUBSYSTEM\tHIERARCHY\tNUM_CGROUPS\tENABLED")
+	for _, name := range sortedSubsystems(cgroupDict) {
+		info := cgroupDict[name]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%t\n", name, info.Hierarchy, info.NumCgroups, info.Enabled)
+	}
+	return tw.Flush()
+}
+
+func sortedSubsystems(cgroupDict map[string]CgroupsInfo) []string {
+	names := make([]string, 0, len(cgroupDict))
+	for name := range cgroupDict {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// print what the discovery found, for -debug-paths: the subsystems of
+// /proc/cgroups, the cgroup mounts, the resolved subsystem paths and the
+// cgroup paths of every container
+func debugPaths(w io.Writer) (err error) {
+	var cgroupDict map[string]CgroupsInfo
+	cgroupDict, err = getCgroups()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", procPath("cgroups"), err.Error())
+	}
+	fmt.Fprintf(w, "subsystems (%s):\n", procPath("cgroups"))
+	for _, name := range sortedSubsystems(cgroupDict) {
+		info := cgroupDict[name]
+		fmt.Fprintf(w, "  %s hierarchy=%d cgroups=%d enabled=%t\n", name, info.Hierarchy, info.NumCgroups, info.Enabled)
+	}
+
+	var mountList []MountInfo
+	mountList, err = getMountInfo()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", mountInfoPath(), err.Error())
+	}
+	fmt.Fprintf(w, "cgroup mounts (%s):\n", mountInfoPath())
+	for _, m := range mountList {
+		if m.FsType != "cgroup" && m.FsType != "cgroup2" {
+			continue
+		}
+		fmt.Fprintf(w, "  %s type=%s root=%s options=%s\n", m.MountPoint, m.FsType, m.Root, m.SuperOption)
+	}
+
+	var cpath map[string]string
+	cpath, err = getCgroupsPath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve the subsystem paths: %s", err.Error())
+	}
+	fmt.Fprintln(w, "subsystem paths:")
+	for _, sub := range sortedKeys(cpath) {
+		fmt.Fprintf(w, "  %s %s\n", sub, cpath[sub])
+	}
+	fmt.Fprintf(w, "cgroup parents: %s\n", strings.Join(cgroupParents, ","))
+
+	var list []string
+	list, err = GetContainerList()
+	if err != nil {
+		return fmt.Errorf("failed to list the containers: %s", err.Error())
+	}
+	fmt.Fprintf(w, "containers (%d):\n", len(list))
+	for _, id := range list {
+		c, cerr := NewContainer(context.Background(), id)
+		if cerr != nil {
+			fmt.Fprintf(w, "  %s error=%s\n", id, cerr.Error())
+			continue
+		}
+		fmt.Fprintf(w, "  %s parent=%s\n", id, c.cgroupParent)
+		for _, sub := range sortedKeys(c.cgroupPath) {
+			fmt.Fprintf(w, "    %s %s\n", sub, c.cgroupPath[sub])
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// the stats of one container as printed by -output json, the name the
+// consumers of the JSON know them by
+type ContainerMetrics = ContainerStats
+
+// read the stats printed by -output json, with or without -stream, or
+// written to the -output-file. The host objects of -age-histogram are
+// skipped, an object of another major schema version is an error. The CPU
+// times are returned in nanoseconds whatever the -time-unit of the
+// collector, the objects before 1.13 without a time_unit had the default
+// ns.
+func Decode(r io.Reader) ([]ContainerMetrics, error) {
+	decoder := json.NewDecoder(r)
+	var stats []ContainerMetrics
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			return stats, nil
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to decode the stats: %w", err)
+		}
+		// an array per poll, an object per container with -stream
+		var poll []ContainerMetrics
+		if bytes.HasPrefix(raw, []byte("[")) {
+			err = json.Unmarshal(raw, &poll)
+		} else {
+			var host HostStats
+			if err = json.Unmarshal(raw, &host); err == nil && host.HostSchemaVersion == "" {
+				var s ContainerMetrics
+				err = json.Unmarshal(raw, &s)
+				poll = append(poll, s)
+			}
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to decode the stats: %w", err)
+		}
+		for i := range poll {
+			s := &poll[i]
+			if err := checkSchemaVersion(s.SchemaVersion); err != nil {
+				return stats, fmt.Errorf("failed to decode the stats of %s: %w", s.Id, err)
+			}
+			if err := s.toNanoseconds(); err != nil {
+				return stats, fmt.Errorf("failed to decode the stats of %s: %w", s.Id, err)
+			}
+		}
+		stats = append(stats, poll...)
+	}
+}
+
+func majorVersion(version string) string {
+	return strings.SplitN(version, ".", 2)[0]
+}
+
+// the fields of another major version were removed or changed their meaning
+func checkSchemaVersion(version string) error {
+	if majorVersion(version) != majorVersion(jsonSchemaVersion) {
+		return fmt.Errorf("schema version %q, can decode %s.x", version, majorVersion(jsonSchemaVersion))
+	}
+	return nil
+}
+
+// scale the CPU times from their time_unit to nanoseconds
+func (this *ContainerStats) toNanoseconds() error {
+	if this.TimeUnit == "" {
+		this.TimeUnit = "ns"
+	}
+	ns, ok := timeUnits[this.TimeUnit]
+	if !ok {
+		return fmt.Errorf("unknown time unit %q", this.TimeUnit)
+	}
+	this.CpuUsage *= ns
+	this.CpuUser *= ns
+	this.CpuSystem *= ns
+	this.CpuThrottledTime *= ns
+	for i := range this.CpuPercpu {
+		this.CpuPercpu[i] *= ns
+	}
+	this.TimeUnit = "ns"
+	return nil
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeRoundTrip(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("e")
+	host.addContainer(id)
+	host.setMemory(id, 4096, 8192)
+	s := sampledContainer(t, host, id).Snapshot()
+	want, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("[" + string(want) + "]")
+	out.WriteString("\n")
+	saved := ageHistogram
+	ageHistogram = true
+	defer func() { ageHistogram = saved }()
+	if err := writeHostStats(&out); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeNDJSON(&out, []ContainerStats{s}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Decode(&out)
+	if err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("decoded %d stats, want the array's and the streamed one", len(stats))
+	}
+	// every field of the JSON is decoded
+	for i, s := range stats {
+		got, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("stats %d decoded as\n%s\nwant\n%s", i, got, want)
+		}
+	}
+}
+
+func TestDecodeSchemaVersion(t *testing.T) {
+	if _, err := Decode(strings.NewReader(`{"schema_version":"2.0","id":"a"}`)); err == nil {
+		t.Error("decoded schema version 2.0")
+	}
+	stats, err := Decode(strings.NewReader(`[{"schema_version":"1.0","id":"a"}]`))
+	if err != nil || len(stats) != 1 {
+		t.Errorf("Decode of schema version 1.0 = %v, %v", stats, err)
+	}
+	if _, err := Decode(strings.NewReader(`[{"id":`)); err == nil {
+		t.Error("decoded truncated JSON")
+	}
+}
+
+func TestDecodeTimeUnit(t *testing.T) {
+	stats, err := Decode(strings.NewReader(`{"schema_version":"1.13","id":"a","time_unit":"ms","cpu_usage":2,"cpu_user":1.5,"cpu_system":0.5,"cpu_percpu":[1,1],"cpu_throttled_time":0.25}
+{"schema_version":"1.12","id":"b","cpu_usage":2000}`))
+	if err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	a, b := stats[0], stats[1]
+	if a.TimeUnit != "ns" || a.CpuUsage != 2e6 || a.CpuUser != 1.5e6 || a.CpuSystem != 0.5e6 || a.CpuPercpu[1] != 1e6 || a.CpuThrottledTime != 0.25e6 {
+		t.Errorf("the ms stats decoded as %+v, want them in ns", a)
+	}
+	// the default before time_unit
+	if b.TimeUnit != "ns" || b.CpuUsage != 2000 {
+		t.Errorf("the stats without a unit decoded as %s %g, want 2000 ns", b.TimeUnit, b.CpuUsage)
+	}
+	if _, err := Decode(strings.NewReader(`{"schema_version":"1.13","id":"a","time_unit":"h"}`)); err == nil {
+		t.Error("decoded a time unit of h")
+	}
+}
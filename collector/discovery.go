@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+)
+
+// where the ids of the containers to collect come from, set by -discovery
+var discovery = "cgroup"
+
+// lists the ids of the running containers each poll
+type Discoverer interface {
+	List(ctx context.Context) ([]string, error)
+}
+
+// the discoverer of the -discovery, or the watcher with -watch
+var discoverer Discoverer = CgroupDiscoverer{}
+
+func newDiscoverer(name string) (Discoverer, error) {
+	switch name {
+	case "cgroup":
+		return CgroupDiscoverer{}, nil
+	case "docker":
+		return DockerAPIDiscoverer{}, nil
+	}
+	return nil, fmt.Errorf("unknown discovery %q, must be cgroup or docker", name)
+}
+
+// scans the docker cgroup dirs of the subsystem mount points
+type CgroupDiscoverer struct{}
+
+func (CgroupDiscoverer) List(ctx context.Context) ([]string, error) {
+	return GetContainerList()
+}
+
+// asks the docker daemon for its running containers, the daemon is the
+// source of truth when the cgroup layout is unknown
+type DockerAPIDiscoverer struct{}
+
+func (DockerAPIDiscoverer) List(ctx context.Context) (list []string, err error) {
+	var summaries []ContainerSummary
+	summaries, err = dockerClient().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the containers from docker: %w", err)
+	}
+	for _, s := range summaries {
+		list = append(list, s.Id)
+	}
+	return
+}
@@ -0,0 +1,2112 @@
+package collector
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"path"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// the unit of the CPU time fields in output, selected by -time-unit
+var timeUnit = "ns"
+
+// nanoseconds per unit
+var timeUnits = map[string]float64{
+	"ns": 1,
+	"us": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+}
+
+// convert a CPU time in nanoseconds into the selected output unit
+func scaleTime(ns float64) float64 {
+	return ns / timeUnits[timeUnit]
+}
+
+// the mount point of procfs, like /host/proc when the collector runs in a
+// container with the host's proc mounted, set by -proc-root
+var procRoot = "/proc"
+
+// a path below procRoot
+func procPath(elem ...string) string {
+	return path.Join(append([]string{procRoot}, elem...)...)
+}
+
+type CgroupsInfo struct {
+	SubsysName string
+	Hierarchy  uint32
+	NumCgroups uint32
+	Enabled    bool
+}
+
+// the fields are separated by tabs, or spaces on some kernels, and the
+// header is "#subsys_name hierarchy num_cgroups enabled"
+func getCgroups() (cgroups map[string]CgroupsInfo, err error) {
+	var out []byte
+
+	out, err = files.ReadFile(procPath("cgroups"))
+	if err != nil {
+		return nil, err
+	}
+	cgroups = make(map[string]CgroupsInfo)
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("failed to parse /proc/cgroups entry %q: %d fields, want 4", line, len(fields))
+		}
+		var hierarchy, num, enabled uint64
+		if hierarchy, err = strconv.ParseUint(fields[1], 10, 32); err == nil {
+			if num, err = strconv.ParseUint(fields[2], 10, 32); err == nil {
+				enabled, err = strconv.ParseUint(fields[3], 10, 32)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse /proc/cgroups entry %q: %s", line, err.Error())
+		}
+		cgroups[fields[0]] = CgroupsInfo{
+			SubsysName: fields[0],
+			Hierarchy:  uint32(hierarchy),
+			NumCgroups: uint32(num),
+			Enabled:    enabled == 1,
+		}
+	}
+	return
+}
+
+// this info read from the /proc/[pid]/mountinfo
+// The file contains lines of the form:
+//
+// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+// (1)(2)(3)   (4)   (5)      (6)      (7)   (8) (9)   (10)         (11)
+//
+// Please see more on http://man7.org/linux/man-pages/man5/proc.5.html
+type MountInfo struct {
+	// (1) mount ID: a unique ID for the mount(may be reused after umount(2)).
+	MountId uint32
+	// (2) parent ID: the ID of the parent mount (or of self for the top of the mount tree).
+	ParentId uint32
+	// (3) major: the value of st_dev for files on this filesystem (see stat(2)).
+	DevMajor uint32
+	// (3) minor: the value of st_dev for files on this filesystem (see stat(2)).
+	DevMinor uint32
+	// (4) root: the pathname of the directory in the filesystem which forms the root of this mount.
+	Root string
+	// (5) mount point: the pathname of the mount point relative to the process's root directory.
+	MountPoint string
+	// (6) mount options: per-mount options.
+	MountOption string
+	// (8) optional fields: zero or more fields of the form "tag[:value]"; see below.
+	OptionField string
+	// (9) filesystem type: the filesystem type in the form "type[.subtype]".
+	FsType string
+	// (10) mount source: filesystem-specific information or "none".
+	MountSource string
+	// (11) super options: per-superblock options.
+	SuperOption string
+}
+
+// the process whose mounts are read, set by -mountinfo-pid. 0 reads the
+// collector's own /proc/self/mountinfo, which in a container lists the
+// container's mounts. PID 1 of the host pid namespace is the host init, so
+// with --pid=host or the host's proc at -proc-root, 1 gives the host view.
+// Its mount points are host paths, so the host's cgroup mounts must be bind
+// mounted at the same place in the container.
+var mountinfoPid int
+
+func mountInfoPath() string {
+	if mountinfoPid > 0 {
+		return procPath(strconv.Itoa(mountinfoPid), "mountinfo")
+	}
+	return procPath("self", "mountinfo")
+}
+
+func getMountInfo() (mount []MountInfo, err error) {
+	var out []byte
+	var n int
+	out, err = files.ReadFile(mountInfoPath())
+	if err != nil {
+		return nil, err
+	}
+	mount = make([]MountInfo, 0)
+
+	for i, line := range strings.Split(string(out), "\n") {
+		var subinfo MountInfo
+
+		if i == 0 || line == "" {
+			continue
+		}
+		// parse the 1 - 6 field
+		n, err = fmt.Sscanf(line, "%d %d %d:%d %s %s %s", &subinfo.MountId, &subinfo.ParentId, &subinfo.DevMajor, &subinfo.DevMinor, &subinfo.Root, &subinfo.MountPoint, &subinfo.MountOption)
+
+		if n != 7 || err != nil {
+			if err == nil {
+				err = fmt.Errorf("failed to parse %s entry %s", mountInfoPath(), line)
+			}
+			return
+		}
+		// parse the field after sep '-', a standalone token after the optional
+		// fields, a '-' inside the mount point or the options is no separator
+		fields := strings.Fields(line)
+		sepindex := -1
+		for j := 6; j < len(fields); j++ {
+			if fields[j] == "-" {
+				sepindex = j
+				break
+			}
+		}
+		if sepindex < 0 || len(fields) < sepindex+4 {
+			err = fmt.Errorf("failed to parse %s entry %s", mountInfoPath(), line)
+			return
+		}
+		subinfo.FsType = fields[sepindex+1]
+		subinfo.MountSource = fields[sepindex+2]
+		subinfo.SuperOption = fields[sepindex+3]
+
+		// process some item like:
+		// 33 29 0:27 / /sys/fs/cgroup/net_cls,net_prio rw,nosuid,nodev,noexec,relatime shared:14 - cgroup cgroup rw,net_cls,net_prio
+		// the co-mounted controllers share the one dir, the per controller
+		// names are symlinks to it which not every distro creates
+		if strings.Contains(subinfo.MountPoint, ",") {
+			dirPath := path.Dir(subinfo.MountPoint)
+			for _, v := range strings.Split(path.Base(subinfo.MountPoint), ",") {
+
+				var sub MountInfo
+				sub = subinfo
+				sub.MountPoint = path.Join(dirPath, v)
+				if fi, serr := files.Stat(sub.MountPoint); serr != nil || !fi.IsDir() {
+					sub.MountPoint = subinfo.MountPoint
+				}
+				mount = append(mount, sub)
+			}
+		} else {
+
+			mount = append(mount, subinfo)
+		}
+	}
+	return
+
+}
+
+type Container struct {
+	id          string
+	name        string
+	imageDigest string
+	// the cgroup dirs by subsystem, read and written under the mutex once
+	// the container is tracked
+	cgroupPath map[string]string
+	// the mount points of the subsystems
+	cgroupRoot map[string]string
+	// the last sample with the CPU usage turned into deltas by UpdateCpu
+	current *cgroups.Stats
+	// the raw cumulative stats of the last sample, untouched by UpdateCpu. The
+	// counters are exported from it and it's the baseline of the next deltas.
+	previous *cgroups.Stats
+	percpu   PercpuSummary
+	// the number of cores the per CPU series were exported for
+	percpuExported int
+	// when the container was discovered
+	firstSeen time.Time
+	// the cgroup parent the container was found under
+	cgroupParent string
+	// the inode of the cgroup dir, see UpdateAge
+	cgroupIno uint64
+	// the restarts seen since the container was discovered
+	restarts uint64
+	// when the last two samples were taken and how long the last took
+	sampled         time.Time
+	previousSampled time.Time
+	collectDuration time.Duration
+	// the cumulative CPU usage of the last sample and the percentage of the
+	// host's CPU capacity used since the sample before
+	cpuCumulative uint64
+	cpuPercent    float64
+	// when cpuCumulative was read, a sample keeping the previous usage
+	// doesn't move it
+	cpuSampled time.Time
+	smoothed   CpuSmoothing
+	// the online host CPUs and the ones the percentage is relative to
+	hostCpus int
+	cpus     int
+	throttle ThrottleInfo
+	quota    CpuQuotaInfo
+	// number of polls the idle container was not sampled, see dueForUpdate
+	skipped int
+	// number of samples taken, delta based metrics need at least two
+	samples int
+	// cumulative and per poll count of the pids limit being hit, from pids.events
+	pidsEvents      uint64
+	pidsEventsDelta uint64
+	hasPidsEvents   bool
+	// page faults during the last poll, from memory.stat
+	pgfault    uint64
+	pgmajfault uint64
+	memory     MemoryInfo
+	oom        OomInfo
+	blkio      BlkioInfo
+	io         IoInfo
+	// the subsystems which can't be read for a permission
+	unavailable []string
+	// the updates failed in a row, see staleAfter
+	failedUpdates int
+	// the huge page usage by page size, nil without the hugetlb controller
+	hugetlb map[string]cgroups.HugetlbStats
+	// the page sizes the hugetlb series were exported for
+	hugetlbExported []string
+	// the PSI of the resources by resource, nil without PSI
+	pressure map[string]Pressure
+	net      NetStats
+	cpuset   CpusetInfo
+	pids     PidsInfo
+	// the smallest memory limit of the cgroup and its ancestors, 0 if unlimited
+	memoryLimit    uint64
+	hasMemoryLimit bool
+	// RDMA usage per device, only when the rdma controller is mounted
+	rdma map[string]RdmaUsage
+	// the devices of the exported RDMA series, to remove the gone ones
+	rdmaExported []string
+	// scheduling of the container's first process, only read with -sched
+	schedPolicy string
+	nice        int
+	// the policy of the docker_container_sched_info series
+	schedExported string
+	// the command of the first process, only read with -command
+	command CommandInfo
+	// metadata of the container, read once when it's discovered
+	labels map[string]string
+	// number of path components below the subsystem mount point
+	cgroupDepth int
+	// other tracked containers have the same cgroup paths, see markShared
+	shared bool
+	// the result of the last collection, see StatusOk etc.
+	status string
+	err    error
+	// StateRunning or StateFrozen when the container is paused
+	state string
+	// carries the container_id field on every log line about the container
+	logger *log.Entry
+	mutex  sync.Mutex
+}
+
+const (
+	// all the subsystems were read
+	StatusOk = "ok"
+	// some of the subsystems failed to read
+	StatusPartial = "partial"
+	// nothing could be read
+	StatusError = "error"
+)
+
+// a copy of the last sample, safe to use while the container keeps updating
+func (this *Container) Stats() (stats cgroups.Stats, status string, err error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.current != nil {
+		stats = *this.current
+	}
+	return stats, this.status, this.err
+}
+
+// export the cumulative counters as they are read instead of the change per
+// poll, for backends computing the rates themselves, set by -no-delta
+var noDelta bool
+
+// the container has not enough samples yet to compute delta based metrics
+func (this *Container) WarmingUp() bool {
+	return !noDelta && this.samples < 2
+}
+
+// summary of the per-CPU usage deltas, so we don't need to export one series
+// per core. A high stddev means the load sits on a few cores only.
+type PercpuSummary struct {
+	Min    float64
+	Max    float64
+	Mean   float64
+	Stddev float64
+}
+
+func summarizePercpu(usage []uint64) (summary PercpuSummary) {
+	n := len(usage)
+	if n == 0 {
+		return
+	}
+	summary.Min = float64(usage[0])
+	summary.Max = float64(usage[0])
+	var sum float64
+	for _, v := range usage {
+		f := float64(v)
+		sum += f
+		if f < summary.Min {
+			summary.Min = f
+		}
+		if f > summary.Max {
+			summary.Max = f
+		}
+	}
+	summary.Mean = sum / float64(n)
+
+	var variance float64
+	for _, v := range usage {
+		d := float64(v) - summary.Mean
+		variance += d * d
+	}
+	summary.Stddev = math.Sqrt(variance / float64(n))
+	return
+}
+
+// read the scheduling policy and nice of each container, set by -sched
+var schedInfo bool
+
+// label the containers with the digest of their image, set by -image-digest
+var imageDigest bool
+
+// ask the docker API for the cgroup paths instead of constructing them, set by -docker-cgroups
+var dockerCgroups bool
+
+func NewContainer(ctx context.Context, id string) (container *Container, err error) {
+	var docker Container
+	var apiPath map[string]string
+	docker.id = id
+	docker.logger = log.WithField("container_id", id)
+	docker.firstSeen = time.Now()
+	docker.cgroupPath = make(map[string]string)
+	cpath := make(map[string]string)
+	cpath, err = getCgroupsPath()
+	if err != nil {
+		return
+	}
+	if dockerCgroups {
+		apiPath, err = getDockerCgroupPath(ctx, id)
+		if err != nil {
+			docker.logger.WithField("error", err.Error()).Debug("failed to get the cgroup path from docker API, fall back to construct it")
+			err = nil
+		}
+	}
+	for k := range cpath {
+		if p, ok := apiPath[k]; ok {
+			docker.cgroupPath[k] = path.Join(cpath[k], p)
+		} else if p, ok := nestedCgroupPath(id); ok {
+			docker.cgroupPath[k], docker.cgroupParent = path.Join(cpath[k], p), path.Dir(p)
+		} else {
+			docker.cgroupPath[k], docker.cgroupParent = containerCgroupDir(cpath[k], id)
+		}
+	}
+	docker.cgroupRoot = cpath
+	docker.cgroupDepth = cgroupDepth(cpath, docker.cgroupPath)
+	docker.UpdateCpuset()
+	if len(xattrLabels) > 0 {
+		docker.labels = readXattrLabels(docker.cgroupPath)
+	}
+	if len(exportLabels) > 0 {
+		if docker.labels == nil {
+			docker.labels = make(map[string]string)
+		}
+		// the xattrs win over the docker labels of the same key
+		for k, v := range readDockerLabels(ctx, id) {
+			if _, ok := docker.labels[k]; !ok {
+				docker.labels[k] = v
+			}
+		}
+	}
+	if len(nameSources) > 0 {
+		docker.name = resolveName(ctx, id)
+	}
+	if imageDigest {
+		var digest string
+		digest, err = dockerClient().ImageDigest(ctx, id)
+		if err != nil {
+			docker.logger.WithField("error", err.Error()).Debug("failed to get the image digest")
+			err = nil
+		}
+		docker.imageDigest = digest
+	}
+	container = &docker
+	return
+}
+
+// export the depth of the cgroup below the subsystem mount point, set by -cgroup-depth
+var exportCgroupDepth bool
+
+// label the output with the cgroup path of each subsystem, set by -cgroup-path-label
+var cgroupPathLabel bool
+
+// the deepest nesting of the cgroup paths below their subsystem mount point
+func cgroupDepth(mounts map[string]string, cpath map[string]string) (depth int) {
+	for k, p := range cpath {
+		rel := strings.Trim(strings.TrimPrefix(p, mounts[k]), "/")
+		if rel == "" {
+			continue
+		}
+		if n := len(strings.Split(rel, "/")); n > depth {
+			depth = n
+		}
+	}
+	return
+}
+
+// get the cgroup path relative to the subsystem mount point from the docker
+// API. The init process's /proc/[pid]/cgroup is the exact path, the cgroup
+// parent is used when the process is not running.
+func getDockerCgroupPath(ctx context.Context, id string) (cpath map[string]string, err error) {
+	var info *ContainerInspect
+	info, err = dockerClient().Inspect(ctx, id)
+	if err != nil {
+		return
+	}
+	if info.State.Pid > 0 {
+		return getProcCgroupPath(info.State.Pid)
+	}
+	if info.HostConfig.CgroupParent == "" {
+		return nil, fmt.Errorf("container %s has neither a pid nor a cgroup parent", id)
+	}
+	var subsystems map[string]string
+	subsystems, err = getCgroupsPath()
+	if err != nil {
+		return
+	}
+	cpath = make(map[string]string)
+	for k := range subsystems {
+		cpath[k] = path.Join(info.HostConfig.CgroupParent, id)
+	}
+	return
+}
+
+// this info read from the /proc/[pid]/cgroup
+// The file contains lines of the form:
+//
+// 4:cpu,cpuacct:/docker/0123456789abcdef
+// (1)    (2)          (3)
+//
+// (1) hierarchy ID, (2) comma separated subsystems, (3) the cgroup path
+func getProcCgroupPath(pid int) (cpath map[string]string, err error) {
+	var out []byte
+	out, err = files.ReadFile(procPath(strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	cpath = make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("failed to parse /proc/%d/cgroup entry %s", pid, line)
+		}
+		// the v2 hierarchy has the ID 0 and no controller list
+		if fields[0] == "0" && fields[1] == "" {
+			cpath[unifiedSubsystem] = fields[2]
+			continue
+		}
+		for _, sub := range strings.Split(fields[1], ",") {
+			if sub != "" {
+				cpath[sub] = fields[2]
+			}
+		}
+	}
+	return
+}
+
+// the key of the cgroup v2 hierarchy in the subsystem path maps. fs.Manager
+// doesn't know it and skips it.
+const unifiedSubsystem = "unified"
+
+// the host mounts both the v1 controllers and the v2 hierarchy
+func isCgroupHybrid(cpath map[string]string) bool {
+	_, ok := cpath[unifiedSubsystem]
+	return ok && len(cpath) > 1
+}
+
+// the subsystem paths are read once per poll instead of for every container,
+// invalidateCgroupsPath drops them at the start of the poll so mounts done
+// meanwhile are picked up
+var cgroupsPathCache struct {
+	sync.Mutex
+	cpath map[string]string
+}
+
+func invalidateCgroupsPath() {
+	cgroupsPathCache.Lock()
+	cgroupsPathCache.cpath = nil
+	cgroupsPathCache.Unlock()
+}
+
+// the subsystems to collect, set by -subsystems. Empty collects all of them.
+var collectedSubsystems []string
+
+// the subsystem is collected. The unified hierarchy is always kept, it holds
+// all the v2 controllers in one dir, and cpu brings cpuacct along as the v1
+// CPU usage is read from there.
+func collectSubsystem(name string) bool {
+	if len(collectedSubsystems) == 0 || name == unifiedSubsystem {
+		return true
+	}
+	for _, s := range collectedSubsystems {
+		if s == name || (s == "cpu" && name == "cpuacct") {
+			return true
+		}
+	}
+	return false
+}
+
+// the subsystem mount points by subsystem name, a copy the caller may keep
+func getCgroupsPath() (cpath map[string]string, err error) {
+	cgroupsPathCache.Lock()
+	defer cgroupsPathCache.Unlock()
+	if cgroupsPathCache.cpath == nil {
+		cgroupsPathCache.cpath, err = readCgroupsPath()
+		if err != nil {
+			return
+		}
+		for k := range cgroupsPathCache.cpath {
+			if !collectSubsystem(k) {
+				delete(cgroupsPathCache.cpath, k)
+			}
+		}
+	}
+	cpath = make(map[string]string, len(cgroupsPathCache.cpath))
+	for k, v := range cgroupsPathCache.cpath {
+		cpath[k] = v
+	}
+	return
+}
+
+func readCgroupsPath() (cpath map[string]string, err error) {
+	var cgroupDict map[string]CgroupsInfo
+	var mountList []MountInfo
+	var disabled []string
+
+	cpath = make(map[string]string)
+
+	err = retry(procReadAttempts, procReadBackoff, func() (err error) {
+		cgroupDict, err = getCgroups()
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading cgroups: %w", err)
+	}
+	if cgroupMountRoot != "" {
+		return getCgroupsPathUnder(cgroupMountRoot, cgroupDict)
+	}
+	err = retry(procReadAttempts, procReadBackoff, func() (err error) {
+		mountList, err = getMountInfo()
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading mountinfo: %w", err)
+	}
+	for _, mnt := range mountList {
+		// on hybrid hosts the v2 hierarchy is mounted next to the v1
+		// controllers, e.g. /sys/fs/cgroup/unified. The files only v2 has
+		// are read from there, the v1 controllers stay on fs.Manager.
+		if mnt.FsType == "cgroup2" {
+			cpath[unifiedSubsystem] = mnt.MountPoint
+			continue
+		}
+		if mnt.FsType != "cgroup" {
+			continue
+		}
+		// a co-mount without the per controller symlinks keeps its combined
+		// dir, like cpu,cpuacct, which holds all of its controllers
+		for _, baseName := range strings.Split(path.Base(mnt.MountPoint), ",") {
+			if cgroupDict[baseName].Enabled {
+				cpath[baseName] = mnt.MountPoint
+			} else if _, ok := cgroupDict[baseName]; ok {
+				disabled = append(disabled, baseName)
+			}
+		}
+	}
+	cgroupsMismatchOnce.Do(func() { logCgroupsMismatch(cgroupDict, cpath, disabled) })
+	return
+}
+
+// the /proc/cgroups and the mounts disagreeing is only logged the first time
+var cgroupsMismatchOnce sync.Once
+
+// log the subsystems enabled in /proc/cgroups without a v1 mount, and the
+// mounted ones which are disabled. Neither gets a path.
+func logCgroupsMismatch(cgroupDict map[string]CgroupsInfo, cpath map[string]string, disabled []string) {
+	unmounted := make([]string, 0)
+	for name, info := range cgroupDict {
+		// hierarchy 0 is the v2 hierarchy, the controller has no v1 mount
+		if _, ok := cpath[name]; !ok && info.Enabled && info.Hierarchy != 0 {
+			unmounted = append(unmounted, name)
+		}
+	}
+	if len(unmounted) > 0 {
+		sort.Strings(unmounted)
+		log.Debugf("the subsystems %s are enabled but not mounted, skip them", strings.Join(unmounted, ","))
+	}
+	if len(disabled) > 0 {
+		sort.Strings(disabled)
+		log.Debugf("the subsystems %s are mounted but disabled, skip them", strings.Join(disabled, ","))
+	}
+}
+
+// the cgroup hierarchy bind mounted elsewhere, like /host/sys/fs/cgroup, set
+// by -cgroup-root. Empty finds the mount points in /proc/self/mountinfo.
+var cgroupMountRoot string
+
+// find the subsystems in the directories below root instead of the mount
+// points, a v1 dir like cpu,cpuacct holds all of its subsystems
+func getCgroupsPathUnder(root string, cgroupDict map[string]CgroupsInfo) (cpath map[string]string, err error) {
+	cpath = make(map[string]string)
+	// the unified hierarchy is the root itself or its unified dir on hybrid hosts
+	if _, err = files.Stat(path.Join(root, "cgroup.controllers")); err == nil {
+		cpath[unifiedSubsystem] = root
+		return
+	}
+	var flist []os.FileInfo
+	flist, err = files.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading cgroup root: %w", err)
+	}
+	for _, f := range flist {
+		dir := path.Join(root, f.Name())
+		// the v1 aliases like cpu -> cpu,cpuacct are symlinks
+		if fi, serr := files.Stat(dir); serr != nil || !fi.IsDir() {
+			continue
+		}
+		if f.Name() == "unified" {
+			cpath[unifiedSubsystem] = dir
+			continue
+		}
+		for _, name := range strings.Split(f.Name(), ",") {
+			if cgroupDict[name].Enabled {
+				cpath[name] = dir
+			}
+		}
+	}
+	return
+}
+
+// the containers tracked across polls, keyed by id. They are kept so the
+// previous sample survives to the next poll.
+var containers = make(map[string]*Container)
+var containersMutex sync.Mutex
+
+// get a tracked container by id
+func lookupContainer(id string) (container *Container, ok bool) {
+	containersMutex.Lock()
+	defer containersMutex.Unlock()
+	container, ok = containers[id]
+	return
+}
+
+// a poll of the containers for the exporters
+type pollResult struct {
+	start time.Time
+	// the containers and the "other" line, nil when the poll failed before
+	// it had any
+	stats  []ContainerStats
+	total  Totals
+	ages   AgeHistogram
+	errors int
+}
+
+// poll and export the containers
+func getCurrentStat(ctx context.Context) error {
+	result, err := collect(ctx)
+	if result.stats == nil {
+		return err
+	}
+	if !baselinePoll {
+		if !pollSummary {
+			printStats(result.stats)
+		}
+		// the host level lines of the human output, the JSON has them in its
+		// host object and /metrics in the host series
+		if options().outputFormat == "human" {
+			if ageHistogram {
+				result.ages.Print()
+			}
+			printFileErrors()
+		}
+		if pollSummary {
+			result.total.LogSummary(result.start, result.errors)
+		}
+	}
+	lastPoll = result.start
+	return err
+}
+
+// list and sample the containers, update their series and retire the ones
+// which are gone. A poll failing all the containers still has their stats.
+func collect(ctx context.Context) (result pollResult, err error) {
+	invalidateCgroupsPath()
+	containerList, err := listContainers(ctx)
+	if err != nil || len(containerList) == 0 {
+		diagnoseEmptyDiscovery()
+	}
+	if err != nil {
+		return
+	}
+	containerList = filterContainers(ctx, containerList)
+	start := time.Now()
+	alive := make(map[string]bool)
+	updated := make([]*Container, 0, len(containerList))
+	var other, total Totals
+	var errors, failed int
+	due := make([]*Container, 0, len(containerList))
+	for _, container := range containerList {
+		alive[container] = true
+		// the poll overran, the remaining containers keep their last sample
+		if ctx.Err() != nil {
+			log.WithFields(log.Fields{
+				"container_id": container,
+				"error":        ctx.Err().Error(),
+			}).Warn("poll timed out")
+			err = fmt.Errorf("poll timed out before %s: %w", container, ctx.Err())
+			return
+		}
+		if !includePause && isPauseContainer(ctx, container) {
+			continue
+		}
+		my, ok := lookupContainer(container)
+		if !ok {
+			my, err = NewContainer(ctx, container)
+			if err != nil {
+				if warnDeduper.allow("failed to create the container", err) {
+					log.WithFields(log.Fields{
+						"container_id": container,
+						"error":        err.Error(),
+					}).Warn("failed to create the container")
+				}
+				errors++
+				failed++
+				continue
+			}
+			containersMutex.Lock()
+			containers[container] = my
+			containersMutex.Unlock()
+		}
+		// the skipped containers keep their last sample
+		updated = append(updated, my)
+		if my.dueForUpdate() {
+			due = append(due, my)
+		}
+	}
+	pool := autoWorkers.workers(len(due))
+	collectorWorkers.Set(float64(pool))
+	collectStart := time.Now()
+	updateErrors, updateFailed := updateContainers(ctx, due, pool)
+	if workers == 0 {
+		autoWorkers.observe(time.Since(collectStart), options().interval)
+	}
+	errors += updateErrors
+	failed += updateFailed
+	warnDeduper.flush()
+	if ctx.Err() != nil {
+		err = fmt.Errorf("poll timed out: %w", ctx.Err())
+		return
+	}
+
+	counted := markShared(updated)
+	var ages AgeHistogram
+	listed := make([]*Container, 0, len(updated))
+	for _, my := range updated {
+		ages.Add(start.Sub(my.firstSeen))
+		if retention > 0 {
+			sampleStore.Add(my)
+		}
+		// containers sharing a cgroup have the same stats, count them once
+		if counted[my] {
+			total.Add(my)
+		}
+		if my.ShortLived() {
+			if counted[my] {
+				other.Add(my)
+			}
+		} else {
+			listed = append(listed, my)
+		}
+	}
+	total.exportMetrics()
+	other.exportOther()
+	setPollAges(ages)
+	collectorContainers.Set(float64(len(updated)))
+	collectorPollSeconds.Set(time.Since(start).Seconds())
+	result = pollResult{start: start, stats: snapshotStats(listed, &other), total: total, ages: ages, errors: errors}
+	// the subscribers get every poll, the ones not printed too
+	subscribers.publish(start, result.stats)
+	// retire the containers which are gone
+	containersMutex.Lock()
+	for id, c := range containers {
+		if !alive[id] {
+			delete(containers, id)
+			deleteContainerMetrics(c)
+		}
+	}
+	containersMutex.Unlock()
+	prunePauseCache(alive)
+
+	if failed > 0 && failed == len(containerList) {
+		err = fmt.Errorf("failed to collect all of the %d containers", failed)
+		return
+	}
+	return result, nil
+}
+
+// the maximum of containers collected concurrently, set by -workers. 0 sizes
+// the pool from the containers and the time their collection takes, see
+// workerPool.
+var workers = 0
+
+// sample the containers with a pool of workers, returns the number of
+// containers with an error and the ones without any stats
+func updateContainers(ctx context.Context, list []*Container, workers int) (errors, failed int) {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	sem := make(chan struct{}, workers)
+	for _, my := range list {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(my *Container) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// the poll overran, the remaining containers keep their last sample
+			if ctx.Err() != nil {
+				my.logger.WithField("error", ctx.Err().Error()).Warn("poll timed out")
+				return
+			}
+			err := my.Update(ctx)
+			if err == nil {
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			errors++
+			if _, status, _ := my.Stats(); status == StatusError {
+				failed++
+				if warnDeduper.allow("failed to get stats", err) {
+					my.logger.WithField("error", err.Error()).Error("failed to get stats")
+				}
+			} else if warnDeduper.allow("failed to get some stats", err) {
+				my.logger.WithField("error", err.Error()).Warn("failed to get some stats")
+			}
+		}(my)
+	}
+	wg.Wait()
+	return
+}
+
+// the address of the HTTP server serving /metrics, set by -listen
+var listenAddr = ":9323"
+
+func serveHTTP(addr string, metrics http.Handler) {
+	log.Infof("listen on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, newServeMux(metrics)))
+}
+
+func newServeMux(metrics http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	mux.HandleFunc("/healthz", serveHealthz)
+	mux.HandleFunc("/container/", serveContainer)
+	mux.HandleFunc("/containers", serveContainers)
+	if retention > 0 {
+		mux.HandleFunc("/query", serveQuery)
+	}
+	return mux
+}
+
+// exit non-zero after strictPolls consecutive failed polls instead of
+// looping forever, set by -strict and -strict-polls
+var strict bool
+var strictPolls = 3
+var failedPolls int
+
+func pollDone(err error) {
+	if err == nil {
+		failedPolls = 0
+		markHealthy(time.Now())
+		return
+	}
+	failedPolls++
+	log.Warnf("poll failed: %s", err.Error())
+	if strict && failedPolls >= strictPolls {
+		log.Fatalf("%d consecutive polls failed, exit", failedPolls)
+	}
+}
+
+func newManager(id string, paths map[string]string) *fs.Manager {
+	return &fs.Manager{
+		Cgroups: &configs.Cgroup{
+			Name: id,
+		},
+		Paths: paths,
+	}
+}
+
+// read the subsystems concurrently with up to subsystemWorkers goroutines,
+// set by -parallel-subsystems and -subsystem-workers
+var parallelSubsystems bool
+var subsystemWorkers = 4
+
+// with fewer subsystems than this the goroutines cost more than they save
+const minParallelSubsystems = 3
+
+// read the stats of all the subsystems. If that fails every subsystem is
+// read on its own, so the readable ones still come through as partial.
+// getStats which gives up when ctx is done, a hung cgroup read is left behind.
+// The goroutine reads a copy of the paths, the one left behind would race
+// with the next Update, and only hands back its result.
+func (this *Container) getStatsContext(ctx context.Context) (stat *cgroups.Stats, status string, err error) {
+	type result struct {
+		stat   *cgroups.Stats
+		status string
+		err    error
+	}
+	reader := &Container{id: this.id, logger: this.logger, cgroupPath: make(map[string]string, len(this.cgroupPath))}
+	for k, p := range this.cgroupPath {
+		reader.cgroupPath[k] = p
+	}
+	done := make(chan result, 1)
+	go func() {
+		stat, status, err := reader.getStats()
+		done <- result{stat, status, err}
+	}()
+	select {
+	case r := <-done:
+		return r.stat, r.status, r.err
+	case <-ctx.Done():
+		return nil, StatusError, fmt.Errorf("reading the stats: %w", ctx.Err())
+	}
+}
+
+func (this *Container) getStats() (stat *cgroups.Stats, status string, err error) {
+	if isCgroupV2(this.cgroupPath) {
+		stat, status, err = getStatsV2(this.cgroupPath[unifiedSubsystem])
+		if err != nil {
+			collectorErrors.WithLabelValues(unifiedSubsystem).Inc()
+		}
+		return
+	}
+	paths := this.existingPaths()
+	if len(paths) == 0 {
+		return nil, StatusError, fmt.Errorf("none of the cgroup dirs of %s exists", this.id)
+	}
+	if parallelSubsystems && len(paths) >= minParallelSubsystems {
+		return this.getSubsystemStats(paths, subsystemWorkers)
+	}
+	stat, err = newManager(this.id, paths).GetStats()
+	if err == nil {
+		return stat, StatusOk, nil
+	}
+	return this.getSubsystemStats(paths, 1)
+}
+
+// read every subsystem with its own manager and merge the results
+// the cgroup paths of the subsystems the container has a dir in, a container
+// may have none for some mounted subsystems like hugetlb or net_prio
+func (this *Container) existingPaths() map[string]string {
+	paths := make(map[string]string, len(this.cgroupPath))
+	skipped := make([]string, 0)
+	// the co-mounted controllers share their dir, it's checked once
+	for dir, names := range coMounted(this.cgroupPath) {
+		if fi, err := files.Stat(dir); err != nil || !fi.IsDir() {
+			skipped = append(skipped, names...)
+			continue
+		}
+		for _, name := range names {
+			paths[name] = dir
+		}
+	}
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		this.logger.WithField("subsystems", strings.Join(skipped, ",")).Debug("skip the subsystems without a cgroup dir")
+	}
+	return paths
+}
+
+func (this *Container) getSubsystemStats(paths map[string]string, workers int) (stat *cgroups.Stats, status string, err error) {
+	type result struct {
+		name string
+		stat *cgroups.Stats
+		err  error
+	}
+	results := make(chan result, len(paths))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	// one manager per dir, the co-mounted controllers like cpu,cpuacct are
+	// read together. Each controller still reads only its own files.
+	for p, names := range coMounted(paths) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(p string, names []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sub := make(map[string]string, len(names))
+			for _, name := range names {
+				sub[name] = p
+			}
+			stat, suberr := newManager(this.id, sub).GetStats()
+			for _, name := range names {
+				results <- result{name, stat, suberr}
+			}
+		}(p, names)
+	}
+	wg.Wait()
+	close(results)
+
+	stat = cgroups.NewStats()
+	failed := &statErrors{}
+	for r := range results {
+		if r.err != nil {
+			countFileError(r.err)
+			collectorErrors.WithLabelValues(r.name).Inc()
+			this.logger.WithFields(log.Fields{
+				"subsystem": r.name,
+				"error":     r.err.Error(),
+			}).Debug("failed to get subsystem stats")
+			failed.add(r.name, r.err)
+			continue
+		}
+		mergeStats(stat, r.stat, r.name)
+	}
+	if len(failed.failed) == 0 {
+		return stat, StatusOk, nil
+	}
+	if len(failed.failed) == len(paths) {
+		return nil, StatusError, failed
+	}
+	return stat, StatusPartial, failed
+}
+
+// the subsystems by their dir, the controllers co-mounted like cpu,cpuacct
+// have the same dir
+func coMounted(paths map[string]string) (dirs map[string][]string) {
+	dirs = make(map[string][]string)
+	for name, p := range paths {
+		dirs[p] = append(dirs[p], name)
+	}
+	for _, names := range dirs {
+		sort.Strings(names)
+	}
+	return
+}
+
+// a subsystem which failed keeps its part of the previous sample and is read
+// again next poll, so a file read mid-write or a subsystem failing for a poll
+// doesn't zero it. A CPU usage of 0 would count as a restart and become the
+// baseline of the next delta. Returns the subsystems kept.
+func (this *Container) keepFailed(stat *cgroups.Stats, status string, err error) (*cgroups.Stats, string, []string) {
+	se, ok := err.(*statErrors)
+	if !ok || len(se.subsystems) == 0 || this.previous == nil {
+		return stat, status, nil
+	}
+	// only the subsystems which didn't parse failed, the sample is complete
+	// with the previous values
+	if stat == nil && len(se.unparsed) == len(se.failed) {
+		stat, status = cgroups.NewStats(), StatusPartial
+	}
+	if stat == nil {
+		return stat, status, nil
+	}
+	previous := copyCpuStats(this.previous)
+	kept := make([]string, 0, len(se.subsystems))
+	for _, name := range se.subsystems {
+		this.logger.WithField("subsystem", name).Debug("failed to get the subsystem stats, keep the previous sample")
+		mergeStats(stat, previous, name)
+		kept = append(kept, name)
+		// the v2 cpu.stat has both the usage and the throttling
+		if name == "cpu" && isCgroupV2(this.cgroupPath) {
+			mergeStats(stat, previous, "cpuacct")
+			kept = append(kept, "cpuacct")
+		}
+	}
+	return stat, status, kept
+}
+
+// copy the part of src filled by the subsystem into dst
+func mergeStats(dst *cgroups.Stats, src *cgroups.Stats, subsystem string) {
+	switch subsystem {
+	case "cpu":
+		dst.CpuStats.ThrottlingData = src.CpuStats.ThrottlingData
+	case "cpuacct":
+		dst.CpuStats.CpuUsage = src.CpuStats.CpuUsage
+	case "memory":
+		dst.MemoryStats = src.MemoryStats
+	case "pids":
+		dst.PidsStats = src.PidsStats
+	case "blkio":
+		dst.BlkioStats = src.BlkioStats
+	case "hugetlb":
+		for k, v := range src.HugetlbStats {
+			dst.HugetlbStats[k] = v
+		}
+	}
+}
+
+// the series of a container are removed after this many updates failed in
+// a row, until one succeeds again, set by -stale-after. 0 keeps them.
+var staleAfter = 3
+
+// log the containers whose collection takes longer than this, set by -slow-collect
+var slowCollect = time.Second
+
+// a copy of the stats which doesn't share the CPU usage with stat
+func copyCpuStats(stat *cgroups.Stats) *cgroups.Stats {
+	copied := *stat
+	copied.CpuStats.CpuUsage.PercpuUsage = append([]uint64(nil), stat.CpuStats.CpuUsage.PercpuUsage...)
+	return &copied
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// take a sample, the error of a partial sample comes with the status
+// StatusPartial and the collected stats are kept
+func (this *Container) Update(ctx context.Context) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	start := time.Now()
+	defer func() {
+		this.collectDuration = time.Since(start)
+		if slowCollect > 0 && this.collectDuration > slowCollect {
+			this.logger.WithField("duration", this.collectDuration.String()).Warn("slow container collection")
+		}
+		// the stale series stay gone, the short lived containers have none
+		if (staleAfter == 0 || this.failedUpdates < staleAfter) && !this.ShortLived() {
+			containerCollectSeconds.WithLabelValues(this.id).Set(this.collectDuration.Seconds())
+		}
+	}()
+	stat, status, err := this.getStatsContext(ctx)
+	this.unavailable = unavailableSubsystems(err)
+	stat, status, kept := this.keepFailed(stat, status, err)
+	this.status = status
+	this.err = err
+	if stat == nil {
+		this.failedUpdates++
+		// the last values would look live on the dashboards
+		if staleAfter > 0 && this.failedUpdates == staleAfter {
+			this.logger.WithField("failed_updates", this.failedUpdates).Warn("stop exporting the stale series of the container")
+			this.deleteMetrics()
+		}
+		// kept when the stale series are gone, the container is still there
+		if !this.ShortLived() {
+			containerUp.WithLabelValues(this.id).Set(0)
+		}
+		return err
+	}
+	this.failedUpdates = 0
+	this.current = stat
+	// UpdateCpu turns the CPU usage of current into deltas in place, the
+	// next sample needs the cumulative values as its baseline
+	cumulative := copyCpuStats(stat)
+	this.previousSampled, this.sampled = this.sampled, start
+	// the cumulative usage, UpdateCpu turns it into a delta
+	cpuUsage := stat.CpuStats.CpuUsage.TotalUsage
+	this.UpdateAge(stat)
+	this.UpdateFreezer()
+	this.UpdateCpuCount(len(stat.CpuStats.CpuUsage.PercpuUsage))
+	// the usage kept from the previous sample wasn't read, the percentage
+	// waits for the next sample which has it
+	if !containsString(kept, "cpuacct") {
+		this.UpdateCpuPercent(cpuUsage, this.cpus)
+	}
+	this.UpdateCpu(stat.CpuStats)
+	this.UpdateCpuQuota()
+	this.UpdateMemory(stat.MemoryStats)
+	this.UpdateBlkio(stat.BlkioStats)
+	this.UpdateIo()
+	this.UpdatePressure()
+	this.UpdateHugetlb(stat.HugetlbStats)
+	this.UpdateNet()
+	this.UpdatePids(stat.PidsStats)
+	this.UpdatePageFaults(stat.MemoryStats)
+	this.UpdatePidsEvents()
+	this.UpdateOom()
+	this.UpdateRdma()
+	this.UpdateMemoryLimit()
+	this.UpdateMemoryUtilization()
+	this.UpdateCpuset()
+	if schedInfo {
+		this.UpdateSched()
+	}
+	if commandInfo {
+		this.UpdateCommand()
+	}
+	// counted before the export, which leaves out the deltas while warming up
+	this.samples++
+	// the short lived containers are summed into the docker_other series,
+	// a series per id would only add cardinality. A restarted container is
+	// short lived again and loses its series.
+	if this.ShortLived() {
+		this.deleteMetrics()
+	} else {
+		this.exportMetrics(cumulative)
+	}
+	this.previous = cumulative
+	return err
+}
+
+// the id of this collector instance stamped on all output, set by -collector-id
+var collectorId string
+
+func collectorLabel() string {
+	if collectorId == "" {
+		return ""
+	}
+	return " collector=" + collectorId
+}
+
+// print the container stats on stdout
+func (this *Container) Print() {
+	this.Fprint(os.Stdout)
+}
+
+// write the container stats line to w
+func (this *Container) Fprint(w io.Writer) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	fmt.Fprintln(w, this.line())
+}
+
+// the key=value stats line, the caller holds the mutex
+func (this *Container) line() string {
+	line := fmt.Sprintf("%s%s status=%s", this.id, collectorLabel(), this.status)
+	// the delta based stats are left out until the second sample
+	if this.WarmingUp() {
+		line += " ready=false"
+	}
+	if this.state != "" {
+		line += " state=" + this.state
+	}
+	if len(this.unavailable) > 0 {
+		line += " unavailable=" + strings.Join(this.unavailable, ",")
+	}
+	line += " age=" + this.Age().Truncate(time.Second).String()
+	if this.restarts > 0 {
+		line += fmt.Sprintf(" restarts=%d", this.restarts)
+	}
+	if this.cgroupParent != "" {
+		line += " cgroup_parent=" + this.cgroupParent
+	}
+	if this.name != "" {
+		line += fmt.Sprintf(" name=%q", this.name)
+	}
+	if this.imageDigest != "" {
+		line += " image_digest=" + this.imageDigest
+	}
+	keys := make([]string, 0, len(this.labels))
+	for k := range this.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		line += fmt.Sprintf(" label.%s=%q", k, this.labels[k])
+	}
+	if this.err != nil {
+		line += fmt.Sprintf(" error=%q", this.err.Error())
+	}
+	if this.shared {
+		line += " shared=true"
+	}
+	line += fmt.Sprintf(" docker_container_collect_seconds=%g", this.collectDuration.Seconds())
+	if exportCgroupDepth {
+		line += fmt.Sprintf(" docker_cgroup_depth=%d", this.cgroupDepth)
+	}
+	if cgroupPathLabel {
+		subsystems := make([]string, 0, len(this.cgroupPath))
+		for k := range this.cgroupPath {
+			subsystems = append(subsystems, k)
+		}
+		sort.Strings(subsystems)
+		for _, k := range subsystems {
+			line += fmt.Sprintf(" cgroup_path.%s=%s", k, this.cgroupPath[k])
+		}
+	}
+	// the CPU usage is delta based, skip it until we have a baseline
+	if this.current != nil && !this.WarmingUp() {
+		line += fmt.Sprintf(" cpu_usage(%s)=%g cpu_percent=%.2f cpus=%d", timeUnit, scaleTime(float64(this.current.CpuStats.CpuUsage.TotalUsage)), this.CpuPercent(), this.cpus)
+		user, system, userPercent, systemPercent := this.CpuBreakdown()
+		line += fmt.Sprintf(" cpu_user(%s)=%g cpu_system(%s)=%g cpu_user_percent=%.2f cpu_system_percent=%.2f",
+			timeUnit, scaleTime(float64(user)), timeUnit, scaleTime(float64(system)), userPercent, systemPercent)
+		line += fmt.Sprintf(" percpu(%s) min=%g max=%g mean=%g stddev=%g", timeUnit,
+			scaleTime(this.percpu.Min), scaleTime(this.percpu.Max), scaleTime(this.percpu.Mean), scaleTime(this.percpu.Stddev))
+	}
+	if this.current != nil && !this.WarmingUp() {
+		line += fmt.Sprintf(" cpu_periods=%d cpu_throttled_periods=%d cpu_throttled_time(%s)=%g cpu_throttled_percent=%.2f",
+			this.throttle.Periods, this.throttle.ThrottledPeriods, timeUnit, scaleTime(float64(this.throttle.ThrottledTime)), this.throttle.Percent)
+	}
+	if this.quota.sampled && this.current != nil && !this.WarmingUp() {
+		line += fmt.Sprintf(" cpu_allocated_cores=%.2f cpu_allocation_percent=%.2f", this.quota.Cores, this.quota.Percent)
+	}
+	if this.cpuset.cpus != "" {
+		line += fmt.Sprintf(" cpuset_cpus=%s cpuset_mems=%s", this.cpuset.cpus, this.cpuset.mems)
+	}
+	if this.schedPolicy != "" {
+		line += fmt.Sprintf(" sched=%s nice=%d", this.schedPolicy, this.nice)
+	}
+	if this.command.Comm != "" {
+		line += fmt.Sprintf(" comm=%s cmdline=%q", this.command.Comm, this.command.Cmdline)
+	}
+	if this.current != nil && !this.WarmingUp() {
+		line += fmt.Sprintf(" pgfault=%d pgmajfault=%d", this.pgfault, this.pgmajfault)
+	}
+	if this.hasPidsEvents && !this.WarmingUp() {
+		line += fmt.Sprintf(" pids_max_events=%d", this.pidsEventsDelta)
+	}
+	if this.current != nil {
+		line += fmt.Sprintf(" memory_usage=%d memory_limit=%d memory_utilization=%.3f memory_cache=%d memory_rss=%d memory_swap=%d",
+			this.memory.Usage, this.memory.Limit, this.memory.Utilization, this.memory.Cache, this.memory.Rss, this.memory.Swap)
+	}
+	if this.hasMemoryLimit {
+		line += fmt.Sprintf(" memory_limit_effective=%d", this.memoryLimit)
+	}
+	if this.oom.sampled && !this.WarmingUp() {
+		line += fmt.Sprintf(" memory_failcnt=%d memory_oom=%d memory_oom_kill=%d", this.oom.FailcntDelta, this.oom.OomDelta, this.oom.OomKillDelta)
+	}
+	if this.current != nil {
+		line += fmt.Sprintf(" pids=%d pids_limit=%d pids_utilization=%.3f", this.pids.Current, this.pids.Limit, this.pids.Utilization)
+	}
+	if this.current != nil && !this.WarmingUp() {
+		line += fmt.Sprintf(" blkio_read_bytes_per_sec=%.0f blkio_write_bytes_per_sec=%.0f", this.blkio.ReadBytesRate, this.blkio.WriteBytesRate)
+	}
+	if this.io.sampled && !this.WarmingUp() {
+		line += fmt.Sprintf(" io_read_bytes_per_sec=%.0f io_write_bytes_per_sec=%.0f io_read_iops=%.1f io_write_iops=%.1f io_discard_bytes_per_sec=%.0f io_discard_iops=%.1f",
+			this.io.ReadBytesRate, this.io.WriteBytesRate, this.io.ReadIosRate, this.io.WriteIosRate, this.io.DiscardBytesRate, this.io.DiscardIosRate)
+	}
+	line += this.pressureLine()
+	line += this.hugetlbLine()
+	if this.net.sampled && !this.WarmingUp() {
+		line += fmt.Sprintf(" net_rx_bytes_per_sec=%.0f net_tx_bytes_per_sec=%.0f", this.net.RxBytesRate, this.net.TxBytesRate)
+	}
+	for _, dev := range this.rdmaDevices() {
+		line += fmt.Sprintf(" rdma.%s.hca_handle=%d rdma.%s.hca_object=%d", dev, this.rdma[dev].HcaHandle, dev, this.rdma[dev].HcaObject)
+	}
+	return line
+}
+
+// the major faults rise when the container is short of memory and pages are reclaimed
+func (this *Container) UpdatePageFaults(stat cgroups.MemoryStats) {
+	if noDelta {
+		this.pgfault = stat.Stats["pgfault"]
+		this.pgmajfault = stat.Stats["pgmajfault"]
+		return
+	}
+	if this.previous == nil {
+		return
+	}
+	previous := this.previous.MemoryStats.Stats
+	this.pgfault = counterDelta(stat.Stats["pgfault"], previous["pgfault"])
+	this.pgmajfault = counterDelta(stat.Stats["pgmajfault"], previous["pgmajfault"])
+}
+
+// read the scheduling policy and nice of the container via its first process
+func (this *Container) UpdateSched() {
+	this.schedPolicy = ""
+	for _, p := range this.cgroupPath {
+		pid, err := readFirstPid(p)
+		if err != nil {
+			continue
+		}
+		policy, nice, err := readSched(pid)
+		if err != nil {
+			this.logger.WithFields(log.Fields{
+				"pid":   pid,
+				"error": err.Error(),
+			}).Debug("failed to read scheduling")
+			return
+		}
+		this.schedPolicy = policy
+		this.nice = nice
+		return
+	}
+}
+
+func (this *Container) UpdateMemoryLimit() {
+	this.hasMemoryLimit = false
+	p, ok := this.controllerPath("memory")
+	if !ok {
+		return
+	}
+	this.memoryLimit, this.hasMemoryLimit = readEffectiveMemoryLimit(this.controllerRoot("memory"), p)
+}
+
+func (this *Container) UpdateRdma() {
+	this.rdma = nil
+	p, ok := this.controllerPath("rdma")
+	if !ok {
+		return
+	}
+	rdma, err := readRdma(p)
+	if err != nil {
+		this.logger.WithFields(log.Fields{
+			"subsystem": "rdma",
+			"error":     err.Error(),
+		}).Debug("failed to read rdma stats")
+		return
+	}
+	this.rdma = rdma
+}
+
+// the RDMA devices in a stable order
+func (this *Container) rdmaDevices() []string {
+	devices := make([]string, 0, len(this.rdma))
+	for dev := range this.rdma {
+		devices = append(devices, dev)
+	}
+	sort.Strings(devices)
+	return devices
+}
+
+func (this *Container) UpdatePidsEvents() {
+	pidsPath, _ := this.controllerPath("pids")
+	max, ok := readPidsEvents(pidsPath)
+	if !ok {
+		this.hasPidsEvents = false
+		return
+	}
+	if noDelta {
+		this.pidsEventsDelta = max
+	} else if this.hasPidsEvents {
+		this.pidsEventsDelta = counterDelta(max, this.pidsEvents)
+	} else {
+		this.pidsEventsDelta = 0
+	}
+	this.pidsEvents = max
+	this.hasPidsEvents = true
+}
+
+// the percentage of the host's CPU capacity the container used between the
+// last two samples, 0 on the first sample
+func (this *Container) CpuPercent() float64 {
+	return this.cpuPercent
+}
+
+// the user and system CPU time of the last sample and their share of the
+// usage in percent, the caller holds the mutex
+func (this *Container) CpuBreakdown() (user, system uint64, userPercent, systemPercent float64) {
+	if this.current == nil {
+		return
+	}
+	usage := this.current.CpuStats.CpuUsage
+	user, system = usage.UsageInUsermode, usage.UsageInKernelmode
+	if usage.TotalUsage > 0 {
+		userPercent = float64(user) / float64(usage.TotalUsage) * 100
+		systemPercent = float64(system) / float64(usage.TotalUsage) * 100
+	}
+	return
+}
+
+// compute the CPU percentage from the cumulative usage, the delta is divided
+// by the elapsed wall-clock time times the CPUs of UpdateCpuCount
+func (this *Container) UpdateCpuPercent(usage uint64, cpus int) {
+	previous, previousSampled := this.cpuCumulative, this.cpuSampled
+	this.cpuCumulative, this.cpuSampled = usage, this.sampled
+	this.cpuPercent = 0
+	if this.previousSampled.IsZero() || previousSampled.IsZero() {
+		return
+	}
+	// a paused container uses no CPU, there's no percentage of the interval
+	if this.Frozen() {
+		this.UpdateCpuSmoothing(false)
+		return
+	}
+	if cpus == 0 {
+		cpus = runtime.NumCPU()
+	}
+	elapsed := this.sampled.Sub(previousSampled)
+	if elapsed <= 0 {
+		return
+	}
+	// the usage went backwards, the interval has no percentage
+	if usage < previous {
+		this.cpuPercentOutOfRange(-float64(previous-usage)/float64(elapsed.Nanoseconds()*int64(cpus))*100, cpus)
+		return
+	}
+	this.cpuPercent = float64(usage-previous) / float64(elapsed.Nanoseconds()*int64(cpus)) * 100
+	// the usage and the wall-clock time aren't read at the same instant, a
+	// busy container can come out a bit above all of its CPUs
+	if limit := float64(100 * cpus); this.cpuPercent > limit {
+		this.cpuPercentOutOfRange(this.cpuPercent, cpus)
+		this.cpuPercent = limit
+	}
+	this.UpdateCpuSmoothing(false)
+}
+
+// the CPU percentages outside of [0, 100 * CPUs] logged so far, the ones
+// after the first cpuPercentLogged are only counted
+var cpuPercentsLogged int
+var cpuPercentsLoggedMutex sync.Mutex
+
+const cpuPercentLogged = 5
+
+func (this *Container) cpuPercentOutOfRange(percent float64, cpus int) {
+	collectorCpuPercentOutOfRange.Inc()
+	cpuPercentsLoggedMutex.Lock()
+	defer cpuPercentsLoggedMutex.Unlock()
+	if cpuPercentsLogged >= cpuPercentLogged {
+		return
+	}
+	cpuPercentsLogged++
+	this.logger.WithFields(log.Fields{
+		"cpu_percent": percent,
+		"cpus":        cpus,
+	}).Warn("the CPU percentage is out of range, clamp it")
+}
+
+func cpuCounters(usage cgroups.CpuUsage) counters {
+	return counters{
+		"total":  usage.TotalUsage,
+		"kernel": usage.UsageInKernelmode,
+		"user":   usage.UsageInUsermode,
+	}
+}
+
+func (this *Container) UpdateCpu(stat cgroups.CpuStats) {
+	// UpdateThrottling keeps its own deltas, the throttling data in current is left cumulative
+	this.UpdateThrottling(stat.ThrottlingData)
+	// the raw cumulative usage is exported as-is
+	if noDelta {
+		this.percpu = summarizePercpu(stat.CpuUsage.PercpuUsage)
+		return
+	}
+
+	// first run the previous is nil
+	if this.previous == nil {
+		return
+	}
+	previous := this.previous.CpuStats.CpuUsage
+	deltas := counterDeltas(cpuCounters(stat.CpuUsage), cpuCounters(previous))
+	this.current.CpuStats.CpuUsage.TotalUsage = deltas["total"]
+	this.current.CpuStats.CpuUsage.UsageInKernelmode = deltas["kernel"]
+	this.current.CpuStats.CpuUsage.UsageInUsermode = deltas["user"]
+	n := len(stat.CpuUsage.PercpuUsage)
+	prev := len(previous.PercpuUsage)
+
+	// a CPU was hotplugged or offlined, the cores don't line up with the
+	// previous sample. This sample becomes the per CPU baseline.
+	if n != prev {
+		this.logger.WithFields(log.Fields{
+			"cpus":          n,
+			"previous_cpus": prev,
+		}).Debug("the number of CPUs changed, reset the per CPU baseline")
+		if prev < n {
+			n = prev
+		}
+		for i := n; i < len(stat.CpuUsage.PercpuUsage); i++ {
+			this.current.CpuStats.CpuUsage.PercpuUsage[i] = 0
+		}
+		for i := 0; i < n; i++ {
+			this.current.CpuStats.CpuUsage.PercpuUsage[i] = counterDelta(stat.CpuUsage.PercpuUsage[i], this.previous.CpuStats.CpuUsage.PercpuUsage[i])
+		}
+		this.percpu = PercpuSummary{}
+		return
+	}
+	for i := 0; i < n; i++ {
+		this.current.CpuStats.CpuUsage.PercpuUsage[i] = counterDelta(stat.CpuUsage.PercpuUsage[i], this.previous.CpuStats.CpuUsage.PercpuUsage[i])
+	}
+
+	this.percpu = summarizePercpu(this.current.CpuStats.CpuUsage.PercpuUsage)
+}
+
+// a container id anywhere in a cgroup directory name, to match both the
+// cgroupfs "<id>" and the systemd "docker-<id>.scope" names
+var containerIdRe = regexp.MustCompile(`\b[0-9a-f]{64}\b`)
+
+// the layouts the docker cgroup drivers create below a subsystem mount point
+type cgroupLayout struct {
+	// the parent directory of the container cgroups
+	parent string
+	// the container directory is prefix + id + suffix
+	prefix string
+	suffix string
+}
+
+var cgroupLayouts = []cgroupLayout{
+	// the cgroupfs driver: docker/<id>
+	{parent: "docker"},
+	// the systemd driver: system.slice/docker-<id>.scope
+	{parent: "system.slice", prefix: "docker-", suffix: ".scope"},
+}
+
+// the layout of a cgroup parent, the systemd driver has slices
+func newCgroupLayout(parent string) cgroupLayout {
+	parent = strings.Trim(parent, "/")
+	if strings.HasSuffix(parent, ".slice") {
+		return cgroupLayout{parent: parent, prefix: "docker-", suffix: ".scope"}
+	}
+	return cgroupLayout{parent: parent}
+}
+
+// the cgroup parents the containers are below, set by -cgroup-parent as a
+// comma separated list like docker,system.slice,docker/burstable. A systemd
+// slice like my.slice holds docker-<id>.scope dirs.
+var cgroupParents = []string{"docker", "system.slice"}
+
+func setCgroupParents(parents []string) {
+	cgroupLayouts = make([]cgroupLayout, 0, len(parents))
+	for _, p := range parents {
+		cgroupLayouts = append(cgroupLayouts, newCgroupLayout(p))
+	}
+}
+
+// the cgroup of the container below the subsystem mount point and the
+// parent it's under, the first layout whose directory exists wins, the
+// first layout otherwise
+func containerCgroupDir(mount string, id string) (dir string, parent string) {
+	for _, l := range cgroupLayouts {
+		dir = path.Join(mount, l.parent, l.prefix+id+l.suffix)
+		if fi, err := files.Stat(dir); err == nil && fi.IsDir() {
+			return dir, l.parent
+		}
+	}
+	l := cgroupLayouts[0]
+	return path.Join(mount, l.parent, l.prefix+id+l.suffix), l.parent
+}
+
+// get the list of the container from cgroup/subsystem/<cgroup parent>
+// like /sys/fs/cgroup/cpu/docker, or cgroup/subsystem/system.slice for the
+// systemd driver
+func GetContainerList() (containerList []string, err error) {
+	var cpath map[string]string
+	cpath, err = getCgroupsPath()
+	if err != nil {
+		return
+	}
+	// the subsystems may list different containers after a partial cleanup,
+	// so take the union of all of them
+	seen := make(map[string]bool)
+	var found int
+	var lerr error
+	for _, sub := range cpath {
+		for _, l := range cgroupLayouts {
+			var flist []os.FileInfo
+			dir := path.Join(sub, l.parent)
+			flist, err = files.ReadDir(dir)
+			if err != nil {
+				// a layout the docker driver doesn't use has no dir, anything
+				// else like a permission error is worth a warning
+				if !os.IsNotExist(err) && warnDeduper.allow("failed to list the docker cgroup dir", err) {
+					log.WithFields(log.Fields{
+						"dir":   dir,
+						"error": err.Error(),
+					}).Warn("failed to list the docker cgroup dir")
+				}
+				if lerr == nil {
+					lerr = err
+				}
+				err = nil
+				continue
+			}
+			found++
+			for _, f := range flist {
+				if !f.IsDir() || !strings.HasPrefix(f.Name(), l.prefix) || !strings.HasSuffix(f.Name(), l.suffix) {
+					continue
+				}
+				if id := containerIdRe.FindString(f.Name()); id != "" && !seen[id] {
+					seen[id] = true
+					containerList = append(containerList, id)
+				}
+			}
+		}
+	}
+	// only give up when no subsystem could be listed
+	if found == 0 && lerr != nil {
+		return nil, lerr
+	}
+	if nestedDepth > 0 {
+		for _, id := range findNestedContainers(cpath) {
+			if !seen[id] {
+				seen[id] = true
+				containerList = append(containerList, id)
+			}
+		}
+	}
+	sort.Strings(containerList)
+	return
+}
+
+// poll every interval until SIGINT or SIGTERM. The signal is only handled
+// between polls, so a running poll is finished first.
+func runLoop(poll func(ctx context.Context) error) {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	// without a config file a SIGHUP still ends the collector
+	reload := make(chan os.Signal, 1)
+	if configFile != "" {
+		signal.Notify(reload, syscall.SIGHUP)
+	}
+
+	next := time.Now()
+	pollDone(pollWithTimeout(poll))
+	for {
+		// a poll which overran skips the ticks it missed
+		if now := time.Now(); next.Before(now) {
+			next = now
+		}
+		next = next.Add(nextInterval())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case sig := <-stop:
+			timer.Stop()
+			log.Infof("got %s, exit", sig)
+			return
+		case <-reload:
+			timer.Stop()
+			if err := reloadConfig(commandLine, configFile); err != nil {
+				log.Errorf("failed to reload the config, keep the previous one: %s", err.Error())
+			}
+			// the next poll is an -interval after the reload
+			next = time.Now()
+		case <-timer.C:
+			pollDone(pollWithTimeout(poll))
+		}
+	}
+}
+
+// randomize each interval by up to this percentage of -interval up or down,
+// so the collectors of a fleet don't read at the same time, set by
+// -interval-jitter. The rates use the measured time between the samples.
+var intervalJitter float64
+
+// the source of the jitter, seeded per process so the collectors started
+// together differ. Only the poll loop draws from it.
+var jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func nextInterval() time.Duration {
+	o := options()
+	if o.intervalJitter <= 0 {
+		return o.interval
+	}
+	jitter := (jitterRand.Float64()*2 - 1) * o.intervalJitter / 100
+	return o.interval + time.Duration(float64(o.interval)*jitter)
+}
+
+// a poll is aborted when it would overrun into the next one
+func pollWithTimeout(poll func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options().interval)
+	defer cancel()
+	return poll(ctx)
+}
+
+// print a single sample and exit, set by -once
+var once bool
+
+// the first poll of -once only takes the baseline of the deltas, nothing is printed
+var baselinePoll bool
+
+// poll twice -interval apart so the deltas have a baseline, print the second
+func runOnce(poll func(ctx context.Context) error) error {
+	baselinePoll = true
+	err := pollWithTimeout(poll)
+	baselinePoll = false
+	if err != nil {
+		return err
+	}
+	time.Sleep(options().interval)
+	return pollWithTimeout(poll)
+}
+
+// the time between two polls, set by -interval, read through options()
+var interval = 3 * time.Second
+
+// the options of the command line which are only read by Run
+var cliOptions struct {
+	version, debugPaths, listSubsystems                                                       bool
+	cgroupParent, pid, subsystems, xattrLabels, labelFilter, exportLabels, filter, nameSource string
+}
+
+// the flag set Run parsed, -config is reloaded into it
+var commandLine = flag.CommandLine
+
+// register the options of the command line on fs, the caller parses it and
+// passes it to Run
+func RegisterFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&interval, "interval", interval, "time between two polls, at least 1s")
+	fs.Float64Var(&cpuSmoothing, "cpu-smoothing", 0, "also export the CPU percentage averaged with this weight of the newest sample, or over about this many samples from 1 on, 0 disables")
+	fs.Float64Var(&intervalJitter, "interval-jitter", 0, "randomize each interval by up to this percentage, 0-50")
+	fs.BoolVar(&cliOptions.version, "version", false, "print the version and exit")
+	fs.BoolVar(&cliOptions.debugPaths, "debug-paths", false, "print the discovered cgroup subsystems, mounts and container paths and exit")
+	fs.BoolVar(&cliOptions.listSubsystems, "list-subsystems", false, "print the subsystems the kernel reports in /proc/cgroups and exit")
+	fs.BoolVar(&once, "once", false, "print one sample taken over -interval and exit")
+	fs.StringVar(&timeUnit, "time-unit", timeUnit, "unit of the CPU time fields of the human, json, influx and statsd output and the gRPC stream, which record it as time_unit: ns|us|ms|s. /metrics keeps the units its series names and help say")
+	fs.StringVar(&collectorId, "collector-id", "", "label stamped on all output to tell co-located collectors apart")
+	fs.BoolVar(&pollSummary, "summary", false, "log one summary line per poll instead of printing each container")
+	fs.DurationVar(&slowCollect, "slow-collect", slowCollect, "log containers whose collection takes longer than this, 0 disables")
+	fs.BoolVar(&ageHistogram, "age-histogram", false, "export the histogram of the container ages each poll")
+	fs.BoolVar(&parallelSubsystems, "parallel-subsystems", false, "read the subsystems of a container concurrently")
+	fs.StringVar(&discovery, "discovery", discovery, "where to list the containers from: cgroup|docker")
+	fs.BoolVar(&watch, "watch", false, "track the containers with inotify on the docker cgroup dirs instead of listing them each poll")
+	fs.BoolVar(&onDemand, "on-demand", false, "take a sample on each scrape of /metrics instead of every -interval, which then bounds a poll")
+	fs.IntVar(&workers, "workers", workers, "the maximum of containers collected concurrently, 0 sizes the pool from the containers and the share of the interval their collection takes")
+	fs.IntVar(&subsystemWorkers, "subsystem-workers", subsystemWorkers, "the maximum of concurrent subsystem reads per container")
+	fs.BoolVar(&noDelta, "no-delta", false, "export the cumulative counters as read instead of the change per poll")
+	fs.BoolVar(&adaptive, "adaptive", false, "sample the containers without CPU usage less frequently")
+	fs.IntVar(&idleEvery, "idle-every", idleEvery, "with -adaptive sample the idle containers every this many polls")
+	fs.BoolVar(&strict, "strict", false, "exit non-zero when the collection keeps failing")
+	fs.IntVar(&staleAfter, "stale-after", staleAfter, "stop exporting a container after this many failed updates in a row, 0 never")
+	fs.IntVar(&strictPolls, "strict-polls", strictPolls, "number of consecutive failed polls before -strict exits")
+	fs.StringVar(&grpcAddr, "grpc-addr", "", "address of the gRPC server streaming the stats of each poll, empty disables it")
+	fs.StringVar(&listenAddr, "listen", listenAddr, "address of the HTTP server serving /metrics, empty disables it")
+	fs.DurationVar(&retention, "retention", 0, "keep the samples of this long in memory for /query, 0 disables")
+	fs.IntVar(&retentionContainers, "retention-containers", retentionContainers, "the maximum of containers kept for /query")
+	fs.StringVar(&dumpFile, "dump-file", "", "file to write the snapshot to on SIGUSR1, default stderr")
+	fs.StringVar(&fifoPath, "fifo", "", "answer container ids written to this FIFO with their stats on <fifo>.reply")
+	fs.StringVar(&procRoot, "proc-root", procRoot, "mount point of the proc filesystem, like /host/proc")
+	fs.IntVar(&mountinfoPid, "mountinfo-pid", 0, "read the mounts of this pid, 1 is the host init with the host's proc, 0 reads self")
+	fs.StringVar(&cliOptions.cgroupParent, "cgroup-parent", strings.Join(cgroupParents, ","), "comma separated cgroup parents the container cgroups are below, like the --cgroup-parent of the docker daemon")
+	fs.IntVar(&nestedDepth, "nested-depth", 0, "also look for containers this many dirs below the cgroup mount points, like docker-in-docker, 0 disables")
+	fs.StringVar(&cgroupMountRoot, "cgroup-root", "", "directory the cgroup hierarchy is mounted at, like /host/sys/fs/cgroup, empty reads the mount points")
+	fs.StringVar(&dockerSocket, "docker-socket", dockerSocket, "docker daemon endpoint: socket path, unix:///path or tcp://host:port, default DOCKER_HOST")
+	fs.StringVar(&dockerTLSCert, "docker-tls-cert", "", "client certificate for a tcp:// docker daemon")
+	fs.StringVar(&dockerTLSKey, "docker-tls-key", "", "client key for a tcp:// docker daemon")
+	fs.StringVar(&dockerTLSCA, "docker-tls-ca", "", "CA to verify a tcp:// docker daemon")
+	fs.IntVar(&otherPolls, "other-polls", 0, "sum containers seen in fewer polls than this into an \"other\" line and the docker_other series, 0 disables")
+	fs.DurationVar(&otherAge, "other-age", 0, "sum containers younger than this into an \"other\" line and the docker_other series, 0 disables")
+	fs.BoolVar(&schedInfo, "sched", false, "read the scheduling policy and nice of each container's first process")
+	fs.DurationVar(&logDedupWindow, "log-dedup-window", logDedupWindow, "log identical collection warnings once within this long, 0 logs all")
+	fs.BoolVar(&commandInfo, "command", false, "label the stats with the command of each container's first process")
+	fs.StringVar(&cliOptions.pid, "pid", "", "comma separated pids to collect the stats of the cgroups of instead of the docker containers")
+	fs.BoolVar(&exportCgroupDepth, "cgroup-depth", false, "export the depth of each container's cgroup below the subsystem mount point")
+	fs.BoolVar(&imageDigest, "image-digest", false, "label the containers with their image digest from the docker API")
+	fs.BoolVar(&includePause, "include-pause", false, "collect the pause/infra sandbox containers too")
+	fs.BoolVar(&cgroupPathLabel, "cgroup-path-label", false, "label each container with its cgroup path per subsystem")
+	fs.BoolVar(&dockerCgroups, "docker-cgroups", false, "read the container cgroup paths from the docker API, fall back to construct them")
+	fs.StringVar(&cliOptions.subsystems, "subsystems", "", "comma separated subsystems to collect, like cpu,memory, empty collects all")
+	fs.StringVar(&cliOptions.xattrLabels, "xattr-labels", "", "comma separated extended attributes of the cgroup dir to export as labels")
+	fs.StringVar(&cliOptions.labelFilter, "label-filter", "", "comma separated docker labels key=value a container must have to be collected")
+	fs.StringVar(&cliOptions.exportLabels, "export-labels", "", "comma separated docker labels to export with the stats")
+	fs.StringVar(&cliOptions.filter, "filter", "", "comma separated container id prefixes or names to collect, empty collects all")
+	fs.StringVar(&cliOptions.nameSource, "name-source", strings.Join(nameSources, ","), "ordered sources of the container name: label:<key>,docker-name,id")
+	fs.StringVar(&outputFormat, "output", outputFormat, "output format: human|json|influx|prometheus|statsd")
+	fs.StringVar(&statsdAddr, "statsd-addr", statsdAddr, "with -output statsd send the gauges to this UDP address")
+	fs.StringVar(&influxUrl, "influx-url", "", "with -output influx POST the lines to this write URL instead of printing them")
+	fs.DurationVar(&pushResync, "push-resync", 0, "with the statsd and influx-url sinks send the slow changing values like the limits only when they change, and all of them this often, 0 sends them every poll")
+	fs.IntVar(&sinkQueue, "sink-queue", sinkQueue, "the polls buffered for the statsd and influx-url sinks, the oldest is dropped when a sink falls behind")
+	fs.BoolVar(&outputStream, "stream", false, "with -output json print one object per line (NDJSON)")
+	fs.StringVar(&outputFile, "output-file", "", "file to also append the stats to as NDJSON")
+	fs.Int64Var(&outputFileMaxSize, "output-file-max-size", outputFileMaxSize, "rotate the -output-file beyond this many bytes, 0 never")
+	fs.IntVar(&outputFileKeep, "output-file-keep", outputFileKeep, "number of rotated -output-file kept")
+	fs.StringVar(&configFile, "config", "", "file with the options as \"flag-name: value\" lines, the command line wins")
+}
+
+// the collector of the command line, with the options of fs parsed. It
+// returns when the collection stops, it exits on invalid options.
+func Run(fs *flag.FlagSet) {
+	commandLine = fs
+	if configFile != "" {
+		if err := loadConfig(fs, configFile); err != nil {
+			log.Fatalf("invalid -config: %s", err.Error())
+		}
+	}
+	if cliOptions.version {
+		fmt.Println(versionString())
+		return
+	}
+	collectedSubsystems = parseList(cliOptions.subsystems)
+	xattrLabels = parseList(cliOptions.xattrLabels)
+	nameSources = parseList(cliOptions.nameSource)
+	pidList, err := parsePids(parseList(cliOptions.pid))
+	if err != nil {
+		log.Fatalf("invalid -pid: %s", err.Error())
+	}
+	processPids = pidList
+	socketSet := false
+	fs.Visit(func(f *flag.Flag) { socketSet = socketSet || f.Name == "docker-socket" })
+	if host := os.Getenv("DOCKER_HOST"); host != "" && !socketSet {
+		dockerSocket = host
+	}
+	tlsConfig, err := loadDockerTLS()
+	if err != nil {
+		log.Fatalf("invalid -docker-tls-*: %s", err.Error())
+	}
+	dockerTLS = tlsConfig
+	cgroupParents = parseList(cliOptions.cgroupParent)
+	for _, p := range cgroupParents {
+		if strings.Trim(p, "/") == "" {
+			log.Fatalf("invalid -cgroup-parent %q", p)
+		}
+	}
+	if len(cgroupParents) == 0 {
+		log.Fatalf("invalid -cgroup-parent, at least one is needed")
+	}
+	setCgroupParents(cgroupParents)
+	exportLabels = parseList(cliOptions.exportLabels)
+	filters, err := parseLabelFilter(parseList(cliOptions.labelFilter))
+	if err != nil {
+		log.Fatalf("invalid -label-filter: %s", err.Error())
+	}
+	registerMetrics()
+	if err := registerLabelsMetric(); err != nil {
+		log.Fatalf("invalid -export-labels: %s", err.Error())
+	}
+	if err := validateNameSources(nameSources); err != nil {
+		log.Fatalf("invalid -name-source: %s", err.Error())
+	}
+	if discoverer, err = newDiscoverer(discovery); err != nil {
+		log.Fatalf("invalid -discovery: %s", err.Error())
+	}
+	if watch && discovery != "cgroup" {
+		log.Fatalf("-watch needs -discovery cgroup")
+	}
+	if sinkQueue < 1 {
+		log.Fatalf("invalid -sink-queue %d, must be at least 1", sinkQueue)
+	}
+	if onDemand && (once || listenAddr == "") {
+		log.Fatalf("-on-demand needs -listen and can't be used with -once")
+	}
+	outputSink, err := newExporter(outputFormat)
+	if err != nil {
+		log.Fatalf("invalid -output: %s", err.Error())
+	}
+	if outputFile != "" {
+		if outputFileKeep < 0 {
+			log.Fatalf("invalid -output-file-keep %d, must not be negative", outputFileKeep)
+		}
+		file, err := newFileExporter(outputFile, outputFileMaxSize, outputFileKeep)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		outputFileSink = file
+	}
+	setOptions(runtimeOptions{
+		interval:        interval,
+		intervalJitter:  intervalJitter,
+		containerFilter: parseList(cliOptions.filter),
+		labelFilter:     filters,
+		outputFormat:    outputFormat,
+		sinkQueue:       sinkQueue,
+		outputSink:      outputSink,
+		exporter:        withOutputFile(outputSink),
+	})
+	if interval < time.Second {
+		log.Fatalf("invalid -interval %s, must be at least 1s", interval)
+	}
+	if intervalJitter < 0 || intervalJitter > 50 {
+		log.Fatalf("invalid -interval-jitter %g, must be between 0 and 50", intervalJitter)
+	}
+	if _, ok := timeUnits[timeUnit]; !ok {
+		log.Fatalf("invalid -time-unit %q, must be one of ns|us|ms|s", timeUnit)
+	}
+	if workers < 0 {
+		log.Fatalf("invalid -workers %d, must be 0 or more", workers)
+	}
+	if subsystemWorkers < 1 {
+		log.Fatalf("invalid -subsystem-workers %d, must be at least 1", subsystemWorkers)
+	}
+	if retentionContainers < 1 {
+		log.Fatalf("invalid -retention-containers %d, must be at least 1", retentionContainers)
+	}
+	if idleEvery < 1 {
+		log.Fatalf("invalid -idle-every %d, must be at least 1", idleEvery)
+	}
+	if cpuSmoothing < 0 {
+		log.Fatalf("invalid -cpu-smoothing %g, must not be negative", cpuSmoothing)
+	}
+	cpuSmoothing = smoothingAlpha(cpuSmoothing)
+	if nestedDepth < 0 || nestedDepth > maxNestedDepth {
+		log.Fatalf("invalid -nested-depth %d, must be 0-%d", nestedDepth, maxNestedDepth)
+	}
+	if staleAfter < 0 {
+		log.Fatalf("invalid -stale-after %d, must not be negative", staleAfter)
+	}
+	if strictPolls < 1 {
+		log.Fatalf("invalid -strict-polls %d, must be at least 1", strictPolls)
+	}
+
+	if cliOptions.debugPaths {
+		if err := debugPaths(os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+	if cliOptions.listSubsystems {
+		if err := listSubsystems(os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	log.Infof("start %s", versionString())
+	if env := detectEnvironment(); env != "" {
+		log.Infof("detected %s, its cgroup layout may hide the containers", env)
+	}
+	if cpath, err := getCgroupsPath(); err == nil && isCgroupHybrid(cpath) {
+		log.Infof("hybrid cgroup layout, the v2 hierarchy is mounted at %s", cpath[unifiedSubsystem])
+	} else if err == nil && isCgroupV2(cpath) {
+		log.Infof("cgroup v2 unified hierarchy mounted at %s", cpath[unifiedSubsystem])
+	}
+	// the servers answer between polls, there is none with -once
+	if !once {
+		if fifoPath != "" {
+			go serveFifo(fifoPath)
+		}
+		go dumpOnSignal()
+		// with -on-demand it starts after the baseline poll
+		if listenAddr != "" && !onDemand {
+			go serveHTTP(listenAddr, metricsHandler())
+		}
+		if grpcAddr != "" {
+			go serveGRPC(grpcAddr)
+		}
+	}
+	if watch && len(processPids) == 0 {
+		w, err := startWatcher()
+		if err != nil {
+			log.Warnf("failed to watch the docker cgroup dirs, list them each poll: %s", err.Error())
+		} else {
+			discoverer = w
+		}
+	}
+	poll := getCurrentStat
+	if len(processPids) > 0 {
+		processes := make([]*Container, 0, len(processPids))
+		for _, pid := range processPids {
+			process, err := NewProcessContainer(pid)
+			if err != nil {
+				log.Fatalf("failed to get the cgroups of pid %d: %s", pid, err.Error())
+			}
+			processes = append(processes, process)
+		}
+		poll = func(ctx context.Context) error { return getProcessStat(ctx, processes) }
+	}
+	if once {
+		if err := runOnce(poll); err != nil {
+			log.Fatalf("poll failed: %s", err.Error())
+		}
+	} else if onDemand {
+		runOnDemand(poll)
+	} else {
+		runLoop(poll)
+	}
+	if err := options().exporter.Flush(); err != nil {
+		log.Warnf("failed to flush the stats: %s", err.Error())
+	}
+	os.Stdout.Sync()
+	log.Info("stopped")
+}
@@ -0,0 +1,423 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"syscall"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestUpdateReadsTheCgroupTree(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	host.setCpu(id, 5000, 2, 1)
+	host.setMemory(id, 4096, 8192)
+	host.setPids(id, 3, 100)
+
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	if err := container.Update(context.Background()); err != nil {
+		t.Fatalf("Update: %s", err)
+	}
+	stats, status, err := container.Stats()
+	if status != StatusOk || err != nil {
+		t.Fatalf("status = %s, %v, want %s", status, err, StatusOk)
+	}
+	// the first sample has no baseline, the usage is the cumulative one
+	if got := stats.CpuStats.CpuUsage.TotalUsage; got != 5000 {
+		t.Errorf("cpu usage = %d, want 5000", got)
+	}
+	if got := stats.CpuStats.CpuUsage.PercpuUsage; len(got) != 2 || got[0] != 2500 || got[1] != 2500 {
+		t.Errorf("per cpu usage = %v, want [2500 2500]", got)
+	}
+	if got := stats.MemoryStats.Usage.Usage; got != 4096 {
+		t.Errorf("memory usage = %d, want 4096", got)
+	}
+	if got := stats.MemoryStats.Usage.Limit; got != 8192 {
+		t.Errorf("memory limit = %d, want 8192", got)
+	}
+	if stats.PidsStats.Current != 3 || stats.PidsStats.Limit != 100 {
+		t.Errorf("pids = %d/%d, want 3/100", stats.PidsStats.Current, stats.PidsStats.Limit)
+	}
+	if container.hostCpus != 2 {
+		t.Errorf("host cpus = %d, want 2", container.hostCpus)
+	}
+	if !container.WarmingUp() {
+		t.Error("the first sample is not warming up")
+	}
+}
+
+func TestGetCgroups(t *testing.T) {
+	host := newFakeHost(t)
+	// the kernels separate the fields with tabs or spaces
+	host.write(procPath("cgroups"), "#subsys_name\thierarchy\tnum_cgroups\tenabled\n"+
+		"cpuset\t9\t12\t1\n"+
+		"memory   5  67 1\n"+
+		"rdma 0 1 0\n\n")
+	got, err := getCgroups()
+	if err != nil {
+		t.Fatalf("getCgroups: %s", err)
+	}
+	want := map[string]CgroupsInfo{
+		"cpuset": {SubsysName: "cpuset", Hierarchy: 9, NumCgroups: 12, Enabled: true},
+		"memory": {SubsysName: "memory", Hierarchy: 5, NumCgroups: 67, Enabled: true},
+		"rdma":   {SubsysName: "rdma", Hierarchy: 0, NumCgroups: 1, Enabled: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d subsystems, want %d: %v", len(got), len(want), got)
+	}
+	for name, info := range want {
+		if got[name] != info {
+			t.Errorf("%s = %+v, want %+v", name, got[name], info)
+		}
+	}
+}
+
+func TestGetCgroupsMalformed(t *testing.T) {
+	host := newFakeHost(t)
+	for _, content := range []string{"cpu 1 2\n", "cpu 1 x 1\n", "cpu 1 2 1 0\n"} {
+		host.write(procPath("cgroups"), content)
+		if _, err := getCgroups(); err == nil {
+			t.Errorf("getCgroups(%q) gave no error", content)
+		}
+	}
+}
+
+func TestGetMountInfo(t *testing.T) {
+	host := newFakeHost(t)
+	// the co-mount has the per controller symlinks, net_cls,net_prio hasn't
+	cgroup := host.cgroupRoot
+	if err := os.Symlink(path.Join(cgroup, "cpu,cpuacct"), path.Join(cgroup, "cpu")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(path.Join(cgroup, "cpu,cpuacct"), path.Join(cgroup, "cpuacct")); err != nil {
+		t.Fatal(err)
+	}
+	host.write(procPath("self", "mountinfo"), "22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"+
+		"30 22 0:26 / "+cgroup+"/cpu,cpuacct rw,nosuid shared:10 master:2 - cgroup cgroup rw,cpu,cpuacct\n"+
+		"31 22 0:27 / "+cgroup+"/net_cls,net_prio rw,nosuid - cgroup cgroup rw,net_cls,net_prio\n"+
+		"32 22 0:28 / "+cgroup+"/unified rw,nosuid shared:4 - cgroup2 cgroup2 rw,nsdelegate\n"+
+		"33 22 0:29 / /mnt/a-b rw - tmpfs - rw\n")
+	mounts, err := getMountInfo()
+	if err != nil {
+		t.Fatalf("getMountInfo: %s", err)
+	}
+	want := []MountInfo{
+		{MountId: 30, ParentId: 22, DevMinor: 26, Root: "/", MountPoint: cgroup + "/cpu", MountOption: "rw,nosuid", FsType: "cgroup", MountSource: "cgroup", SuperOption: "rw,cpu,cpuacct"},
+		{MountId: 30, ParentId: 22, DevMinor: 26, Root: "/", MountPoint: cgroup + "/cpuacct", MountOption: "rw,nosuid", FsType: "cgroup", MountSource: "cgroup", SuperOption: "rw,cpu,cpuacct"},
+		{MountId: 31, ParentId: 22, DevMinor: 27, Root: "/", MountPoint: cgroup + "/net_cls,net_prio", MountOption: "rw,nosuid", FsType: "cgroup", MountSource: "cgroup", SuperOption: "rw,net_cls,net_prio"},
+		{MountId: 31, ParentId: 22, DevMinor: 27, Root: "/", MountPoint: cgroup + "/net_cls,net_prio", MountOption: "rw,nosuid", FsType: "cgroup", MountSource: "cgroup", SuperOption: "rw,net_cls,net_prio"},
+		{MountId: 32, ParentId: 22, DevMinor: 28, Root: "/", MountPoint: cgroup + "/unified", MountOption: "rw,nosuid", FsType: "cgroup2", MountSource: "cgroup2", SuperOption: "rw,nsdelegate"},
+		{MountId: 33, ParentId: 22, DevMinor: 29, Root: "/", MountPoint: "/mnt/a-b", MountOption: "rw", FsType: "tmpfs", MountSource: "-", SuperOption: "rw"},
+	}
+	if len(mounts) != len(want) {
+		t.Fatalf("got %d mounts, want %d: %+v", len(mounts), len(want), mounts)
+	}
+	for i := range want {
+		if mounts[i] != want[i] {
+			t.Errorf("mount %d = %+v, want %+v", i, mounts[i], want[i])
+		}
+	}
+}
+
+func TestGetMountInfoMalformed(t *testing.T) {
+	host := newFakeHost(t)
+	for _, line := range []string{
+		"30 22 0:26 / /sys/fs/cgroup/cpu rw\n",
+		"30 22 0:26 / /sys/fs/cgroup/cpu rw shared:1 cgroup cgroup rw\n",
+		"30 22 0:26 / /sys/fs/cgroup/cpu rw - cgroup\n",
+	} {
+		host.write(procPath("self", "mountinfo"), "22 1 8:1 / / rw - ext4 /dev/sda1 rw\n"+line)
+		if _, err := getMountInfo(); err == nil {
+			t.Errorf("getMountInfo(%q) gave no error", line)
+		}
+	}
+}
+
+func TestGetContainerListLayouts(t *testing.T) {
+	host := newFakeHost(t)
+	cgroupfs, systemd := fakeId("a"), fakeId("b")
+	host.addContainer(cgroupfs)
+	host.addScope(systemd)
+	// neither a container dir nor a docker scope
+	host.mkdir(path.Join(host.dirs["memory"], "docker", "not-a-container"))
+	host.mkdir(path.Join(host.dirs["memory"], "system.slice", "cron.service"))
+	host.mkdir(path.Join(host.dirs["memory"], "system.slice", fakeId("c")+".scope"))
+	list, err := GetContainerList()
+	if err != nil {
+		t.Fatalf("GetContainerList: %s", err)
+	}
+	if len(list) != 2 || list[0] != cgroupfs || list[1] != systemd {
+		t.Errorf("got %v, want [%s %s]", list, cgroupfs, systemd)
+	}
+}
+
+func TestGetContainerListUnion(t *testing.T) {
+	host := newFakeHost(t)
+	// a partial cleanup left a in memory only and c in pids only, b is in both
+	a, b, c := fakeId("a"), fakeId("b"), fakeId("c")
+	host.mkdir(host.containerDir("memory", a))
+	host.mkdir(host.containerDir("memory", b))
+	host.mkdir(host.containerDir("pids", b))
+	host.mkdir(host.containerDir("pids", c))
+	list, err := GetContainerList()
+	if err != nil {
+		t.Fatalf("GetContainerList: %s", err)
+	}
+	if len(list) != 3 || list[0] != a || list[1] != b || list[2] != c {
+		t.Errorf("got %v, want each of [%s %s %s] once", list, a, b, c)
+	}
+}
+
+func TestGetContainerListUnreadableDir(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	unreadable := path.Join(host.dirs["memory"], "docker")
+	files = failingReader{
+		fail: func(name string) bool { return name == unreadable },
+		err:  &os.PathError{Op: "open", Path: unreadable, Err: syscall.EACCES},
+	}
+	list, err := GetContainerList()
+	if err != nil {
+		t.Fatalf("GetContainerList: %s", err)
+	}
+	if len(list) != 1 || list[0] != id {
+		t.Errorf("got %v, want [%s] from the readable subsystems", list, id)
+	}
+
+	// nothing could be listed, the error comes through
+	files = failingReader{
+		fail: func(name string) bool { return path.Base(name) == "docker" || path.Base(name) == "system.slice" },
+		err:  &os.PathError{Op: "open", Path: unreadable, Err: syscall.EACCES},
+	}
+	if list, err = GetContainerList(); err == nil {
+		t.Errorf("got %v without an error, want the permission error", list)
+	}
+}
+
+func TestUpdateDeltasAcrossSamples(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	// the delta of each sample is against the cumulative usage of the one
+	// before, not against the delta UpdateCpu left in current
+	for i, sample := range []struct {
+		total uint64
+		want  uint64
+		// the per CPU delta of each of the two CPUs
+		wantPercpu uint64
+	}{
+		{total: 10000, want: 10000, wantPercpu: 5000},
+		{total: 16000, want: 6000, wantPercpu: 3000},
+		{total: 20000, want: 4000, wantPercpu: 2000},
+	} {
+		host.setCpu(id, sample.total, 0, 0)
+		if err := container.Update(context.Background()); err != nil {
+			t.Fatalf("Update %d: %s", i, err)
+		}
+		stats, _, _ := container.Stats()
+		usage := stats.CpuStats.CpuUsage
+		if usage.TotalUsage != sample.want {
+			t.Errorf("sample %d: cpu usage = %d, want %d", i, usage.TotalUsage, sample.want)
+		}
+		if len(usage.PercpuUsage) != 2 || usage.PercpuUsage[0] != sample.wantPercpu || usage.PercpuUsage[1] != sample.wantPercpu {
+			t.Errorf("sample %d: per cpu usage = %v, want %d each", i, usage.PercpuUsage, sample.wantPercpu)
+		}
+		if container.previous.CpuStats.CpuUsage.TotalUsage != sample.total {
+			t.Errorf("sample %d: baseline = %d, want the cumulative %d", i, container.previous.CpuStats.CpuUsage.TotalUsage, sample.total)
+		}
+	}
+	if container.restarts != 0 {
+		t.Errorf("restarts = %d, want 0", container.restarts)
+	}
+}
+
+func TestUpdateKeepsTheFailedSubsystem(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	update := func(total uint64) {
+		t.Helper()
+		host.setCpu(id, total, 0, 0)
+		host.setMemory(id, total, 0)
+		container.Update(context.Background())
+	}
+	update(1000)
+	update(2000)
+
+	// cpuacct can't be read for a poll, memory still can
+	usage := path.Join(host.containerDir("cpuacct", id), "cpuacct.usage")
+	if err := os.Remove(usage); err != nil {
+		t.Fatal(err)
+	}
+	host.setMemory(id, 3e9, 0)
+	if err := container.Update(context.Background()); err == nil {
+		t.Fatal("Update without cpuacct.usage gave no error")
+	}
+	stats, status, _ := container.Stats()
+	if status != StatusPartial {
+		t.Errorf("status = %s, want %s", status, StatusPartial)
+	}
+	if stats.MemoryStats.Usage.Usage != 3e9 {
+		t.Errorf("memory usage = %d, want the new 3e9", stats.MemoryStats.Usage.Usage)
+	}
+	// the usage of the previous sample is kept: no restart, no delta
+	if container.restarts != 0 {
+		t.Errorf("restarts = %d, want 0", container.restarts)
+	}
+	if got := container.previous.CpuStats.CpuUsage.TotalUsage; got != 2000 {
+		t.Errorf("baseline = %d, want the kept 2000", got)
+	}
+	if got := stats.CpuStats.CpuUsage.TotalUsage; got != 0 {
+		t.Errorf("cpu usage delta = %d, want 0", got)
+	}
+	// the percentage waits for the usage to be read again
+	cpuSampled := container.cpuSampled
+	if !cpuSampled.Equal(container.previousSampled) {
+		t.Errorf("the CPU usage read at %s moved to the sample without it", cpuSampled)
+	}
+
+	// the next full sample is a delta against the kept usage, not against 0
+	update(3000)
+	stats, _, _ = container.Stats()
+	if got := stats.CpuStats.CpuUsage.TotalUsage; got != 1000 {
+		t.Errorf("cpu usage delta = %d, want 1000", got)
+	}
+	// the percentage is over the time since the usage was last read, the
+	// usage is small enough for it not to be clamped
+	elapsed := container.sampled.Sub(cpuSampled)
+	if want := float64(1000) / float64(elapsed.Nanoseconds()*2) * 100; container.cpuPercent != want {
+		t.Errorf("cpu percent = %f, want %f", container.cpuPercent, want)
+	}
+	if container.restarts != 0 {
+		t.Errorf("restarts = %d, want 0", container.restarts)
+	}
+}
+
+func TestUpdateContainerUp(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	container.Update(context.Background())
+	if got := testutil.ToFloat64(containerUp.WithLabelValues(id)); got != 1 {
+		t.Errorf("up = %f after a sample, want 1", got)
+	}
+	// nothing can be read
+	for name := range fakeSubsystems {
+		if err := os.RemoveAll(host.containerDir(name, id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < staleAfter; i++ {
+		container.Update(context.Background())
+		if got := testutil.ToFloat64(containerUp.WithLabelValues(id)); got != 0 {
+			t.Errorf("up = %f after %d failed updates, want 0", got, i+1)
+		}
+	}
+	deleteContainerMetrics(container)
+}
+
+func TestUpdateCollectSeconds(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	container.Update(context.Background())
+	stats := container.Snapshot()
+	if stats.CollectSeconds <= 0 {
+		t.Errorf("collect seconds = %f, want the collection time", stats.CollectSeconds)
+	}
+	if got := testutil.ToFloat64(containerCollectSeconds.WithLabelValues(id)); got != stats.CollectSeconds {
+		t.Errorf("docker_container_collect_seconds = %f, want %f", got, stats.CollectSeconds)
+	}
+	deleteContainerMetrics(container)
+}
+
+func TestReadCgroupsPathReadErrors(t *testing.T) {
+	newFakeHost(t)
+	// -cgroup-root would skip the mountinfo
+	cgroupMountRoot = ""
+	for _, file := range []string{"cgroups", "mountinfo"} {
+		failed := &os.PathError{Op: "open", Path: file, Err: syscall.EACCES}
+		files = failingReader{
+			fail: func(name string) bool { return path.Base(name) == file },
+			err:  failed,
+		}
+		cpath, err := readCgroupsPath()
+		if !errors.Is(err, syscall.EACCES) {
+			t.Errorf("an unreadable %s gave %v, %v, want its permission error", file, cpath, err)
+		}
+	}
+}
+
+func TestReadCgroupsPathHybrid(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	unified := path.Join(host.cgroupRoot, "unified")
+	host.mkdir(unified)
+	// the first line is skipped like the root mount of the namespace
+	host.write(path.Join(host.procRoot, "self", "mountinfo"), fmt.Sprintf(
+		"22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"+
+			"25 22 0:23 / %[1]s ro shared:9 - tmpfs tmpfs ro,mode=755\n"+
+			"26 25 0:24 / %[1]s/unified rw shared:10 - cgroup2 cgroup2 rw,nsdelegate\n"+
+			"28 25 0:26 / %[1]s/cpu,cpuacct rw shared:12 - cgroup cgroup rw,cpu,cpuacct\n"+
+			"29 25 0:27 / %[1]s/memory rw shared:13 - cgroup cgroup rw,memory\n"+
+			"30 25 0:28 / %[1]s/pids rw shared:14 - cgroup cgroup rw,pids\n", host.cgroupRoot))
+	host.write(path.Join(host.procRoot, "1234", "cgroup"),
+		"4:pids:/docker/"+id+"\n"+
+			"3:memory:/docker/"+id+"\n"+
+			"2:cpu,cpuacct:/docker/"+id+"\n"+
+			"0::/docker/"+id+"\n")
+	cgroupMountRoot = ""
+
+	mounts, err := readCgroupsPath()
+	if err != nil {
+		t.Fatalf("readCgroupsPath: %s", err)
+	}
+	if !isCgroupHybrid(mounts) || isCgroupV2(mounts) {
+		t.Errorf("the mounts %v aren't hybrid", mounts)
+	}
+	cpath, err := getProcCgroupPath(1234)
+	if err != nil {
+		t.Fatalf("getProcCgroupPath: %s", err)
+	}
+	want := map[string]string{
+		"cpu":            path.Join(host.dirs["cpu"], "docker", id),
+		"cpuacct":        path.Join(host.dirs["cpuacct"], "docker", id),
+		"memory":         path.Join(host.dirs["memory"], "docker", id),
+		"pids":           path.Join(host.dirs["pids"], "docker", id),
+		unifiedSubsystem: path.Join(unified, "docker", id),
+	}
+	if len(mounts) != len(want) {
+		t.Errorf("got the subsystems %v, want %d", mounts, len(want))
+	}
+	for k, dir := range want {
+		if got := path.Join(mounts[k], cpath[k]); got != dir {
+			t.Errorf("%s resolves to %s, want %s", k, got, dir)
+		}
+	}
+}
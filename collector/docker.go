@@ -0,0 +1,204 @@
+package collector
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the docker daemon endpoint, set by -docker-socket or DOCKER_HOST: the path
+// of the unix socket, unix:///path or tcp://host:port
+var dockerSocket = "/var/run/docker.sock"
+
+// the client certificate of a tcp:// daemon, set by -docker-tls-cert, -key and -ca
+var dockerTLSCert, dockerTLSKey, dockerTLSCA string
+
+// built from the -docker-tls-* flags, nil talks plain HTTP to a tcp:// daemon
+var dockerTLS *tls.Config
+
+func loadDockerTLS() (config *tls.Config, err error) {
+	if dockerTLSCert == "" && dockerTLSKey == "" && dockerTLSCA == "" {
+		return nil, nil
+	}
+	config = &tls.Config{}
+	if dockerTLSCert != "" || dockerTLSKey != "" {
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(dockerTLSCert, dockerTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if dockerTLSCA != "" {
+		var ca []byte
+		ca, err = ioutil.ReadFile(dockerTLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in the CA %s", dockerTLSCA)
+		}
+	}
+	return
+}
+
+// talk to the docker engine API over the unix socket or TCP
+type DockerClient struct {
+	client *http.Client
+	// the URL the API paths are appended to
+	base string
+}
+
+func NewDockerClient(endpoint string) *DockerClient {
+	transport := &http.Transport{}
+	base := "http://docker"
+	if strings.HasPrefix(endpoint, "tcp://") {
+		host := strings.TrimPrefix(endpoint, "tcp://")
+		base = "http://" + host
+		if dockerTLS != nil {
+			base = "https://" + host
+			transport.TLSClientConfig = dockerTLS
+		}
+	} else {
+		socket := strings.TrimPrefix(endpoint, "unix://")
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+	return &DockerClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   5 * time.Second,
+		},
+		base: base,
+	}
+}
+
+// the client of dockerSocket all the callers share, its transport keeps the
+// idle connections for the next request instead of one transport and its
+// connections per request
+var sharedDockerClient struct {
+	sync.Mutex
+	endpoint string
+	client   *DockerClient
+}
+
+func dockerClient() *DockerClient {
+	sharedDockerClient.Lock()
+	defer sharedDockerClient.Unlock()
+	if sharedDockerClient.client == nil || sharedDockerClient.endpoint != dockerSocket {
+		if sharedDockerClient.client != nil {
+			sharedDockerClient.client.client.CloseIdleConnections()
+		}
+		sharedDockerClient.client = NewDockerClient(dockerSocket)
+		sharedDockerClient.endpoint = dockerSocket
+	}
+	return sharedDockerClient.client
+}
+
+// the part of the `docker inspect` output we are interested in
+type ContainerInspect struct {
+	Id    string
+	Name  string
+	Image string
+	State struct {
+		Running bool
+		Pid     int
+	}
+	HostConfig struct {
+		CgroupParent string
+	}
+	Config struct {
+		Image  string
+		Labels map[string]string
+	}
+}
+
+func (this *DockerClient) get(ctx context.Context, url string, v interface{}) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, this.base+url, nil)
+	if err != nil {
+		return
+	}
+	resp, err := this.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker API %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// an entry of the `docker ps` output
+type ContainerSummary struct {
+	Id     string
+	Names  []string
+	Image  string
+	Labels map[string]string
+}
+
+// the name without the leading slash the API puts in front of it
+func (this *ContainerSummary) Name() string {
+	if len(this.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(this.Names[0], "/")
+}
+
+// list the running containers
+func (this *DockerClient) List(ctx context.Context) (list []ContainerSummary, err error) {
+	err = this.get(ctx, "/containers/json", &list)
+	return
+}
+
+func (this *DockerClient) Inspect(ctx context.Context, id string) (info *ContainerInspect, err error) {
+	info = &ContainerInspect{}
+	if err = this.get(ctx, "/containers/"+id+"/json", info); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// the part of `docker image inspect` output we are interested in
+type ImageInspect struct {
+	Id          string
+	RepoDigests []string
+}
+
+func (this *DockerClient) InspectImage(ctx context.Context, id string) (info *ImageInspect, err error) {
+	info = &ImageInspect{}
+	if err = this.get(ctx, "/images/"+id+"/json", info); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// the digest of the image the container runs, the repo digest such as
+// nginx@sha256:... when the image was pulled, the local image ID otherwise
+func (this *DockerClient) ImageDigest(ctx context.Context, id string) (digest string, err error) {
+	var container *ContainerInspect
+	var image *ImageInspect
+	container, err = this.Inspect(ctx, id)
+	if err != nil {
+		return
+	}
+	image, err = this.InspectImage(ctx, container.Image)
+	if err != nil {
+		return
+	}
+	if len(image.RepoDigests) > 0 {
+		return image.RepoDigests[0], nil
+	}
+	return image.Id, nil
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+)
+
+// a docker API on a unix socket under t.TempDir() serving the containers,
+// dockerSocket points to it until the test ends
+func newFakeDocker(t *testing.T, containers ...ContainerSummary) {
+	t.Helper()
+	socket := path.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(containers)
+	})
+	mux.HandleFunc("/containers/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/json")
+		for _, c := range containers {
+			if c.Id == id {
+				var info ContainerInspect
+				info.Id, info.Name, info.Image = c.Id, "/"+c.Name(), c.Image
+				info.Config.Image, info.Config.Labels = c.Image, c.Labels
+				json.NewEncoder(w).Encode(info)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	saved := dockerSocket
+	dockerSocket = socket
+	t.Cleanup(func() {
+		dockerSocket = saved
+		server.Close()
+	})
+}
+
+func TestDockerClientShared(t *testing.T) {
+	defer func(saved string) { dockerSocket = saved }(dockerSocket)
+	dockerSocket = "/run/a.sock"
+	first := dockerClient()
+	if dockerClient() != first {
+		t.Error("a second client was created for the same endpoint")
+	}
+	dockerSocket = "/run/b.sock"
+	if dockerClient() == first {
+		t.Error("the client of the old endpoint was kept")
+	}
+}
@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// where to write the snapshot on SIGUSR1, set by -dump-file. Empty means stderr.
+var dumpFile string
+
+// write the last sample of every tracked container to w
+func dumpContainers(w io.Writer) {
+	containersMutex.Lock()
+	list := make([]*Container, 0, len(containers))
+	for _, c := range containers {
+		list = append(list, c)
+	}
+	containersMutex.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	for _, c := range list {
+		c.Fprint(w)
+	}
+}
+
+// dump the snapshot of all containers each time SIGUSR1 arrives, without
+// waiting for the next poll
+func dumpOnSignal() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGUSR1)
+	for range sig {
+		if dumpFile == "" {
+			dumpContainers(os.Stderr)
+			continue
+		}
+		f, err := os.OpenFile(dumpFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			log.Warnf("failed to open dump file %s: %s", dumpFile, err.Error())
+			continue
+		}
+		dumpContainers(f)
+		f.Close()
+		log.Infof("dumped the container snapshot to %s", dumpFile)
+	}
+}
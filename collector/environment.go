@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"path"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	// the LinuxKit VM of Docker Desktop for Mac and Windows
+	envDockerDesktop = "docker-desktop"
+	// the Windows Subsystem for Linux 2 VM
+	envWSL2 = "wsl2"
+)
+
+// tell the VM environments with an unusual cgroup layout from /proc/version
+func detectEnvironment() string {
+	out, err := files.ReadFile(procPath("version"))
+	if err != nil {
+		return ""
+	}
+	version := strings.ToLower(string(out))
+	switch {
+	case strings.Contains(version, "linuxkit"):
+		return envDockerDesktop
+	case strings.Contains(version, "microsoft") || strings.Contains(version, "wsl"):
+		return envWSL2
+	}
+	return ""
+}
+
+// the empty discovery was explained already
+var emptyDiscoveryLogged bool
+
+// explain why no container was found, once
+func diagnoseEmptyDiscovery() {
+	if emptyDiscoveryLogged {
+		return
+	}
+	emptyDiscoveryLogged = true
+
+	cpath, err := getCgroupsPath()
+	if err != nil {
+		log.Warnf("no container found, the cgroup discovery failed: %s", err.Error())
+		return
+	}
+	switch detectEnvironment() {
+	case envDockerDesktop:
+		log.Warnf("no container found: running on Docker Desktop, the containers live in the LinuxKit VM " +
+			"and their cgroups are not visible from the host. Run the collector in a container with the VM's " +
+			"/sys/fs/cgroup mounted instead.")
+	case envWSL2:
+		log.Warnf("no container found: running on WSL2, where the docker cgroups are under the docker-desktop " +
+			"distribution. Run the collector inside that distribution.")
+	default:
+		switch {
+		case len(cpath) == 0:
+			log.Warnf("no container found: neither a cgroup v1 subsystem nor the v2 hierarchy is mounted")
+		case isCgroupV2(cpath):
+			diagnoseEmptyV2(cpath[unifiedSubsystem])
+		default:
+			log.Warnf("no container found under the docker cgroup of the subsystems %v", cpath)
+		}
+	}
+}
+
+// the controllers the v2 stats are read from
+var v2Controllers = []string{"cpu", "memory", "pids"}
+
+// a v2 hierarchy without the controllers in cgroup.controllers has
+// cgroups without their stat files
+func diagnoseEmptyV2(root string) {
+	file := path.Join(root, "cgroup.controllers")
+	out, err := files.ReadFile(file)
+	if err != nil {
+		log.Warnf("no container found and the v2 hierarchy at %s has no readable %s: %s", root, file, err.Error())
+		return
+	}
+	enabled := make(map[string]bool)
+	for _, c := range strings.Fields(string(out)) {
+		enabled[c] = true
+	}
+	missing := make([]string, 0)
+	for _, c := range v2Controllers {
+		if !enabled[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		log.Warnf("no container found under the v2 hierarchy at %s, the controllers %s are not in %s",
+			root, strings.Join(missing, ","), file)
+		return
+	}
+	log.Warnf("no container found under the docker cgroups of the v2 hierarchy at %s", root)
+}
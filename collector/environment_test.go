@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+func TestDiagnoseEmptyV2(t *testing.T) {
+	host := newFakeHost(t)
+	root := path.Join(host.cgroupRoot, "v2")
+	var buf bytes.Buffer
+	defer log.SetOutput(log.StandardLogger().Out)
+	log.SetOutput(&buf)
+	tests := []struct {
+		controllers string
+		want        string
+	}{
+		{"cpuset cpu io memory pids\n", "under the docker cgroups of the v2 hierarchy"},
+		{"cpuset io\n", "the controllers cpu,memory,pids are not in"},
+		{"", "the controllers cpu,memory,pids are not in"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		host.write(path.Join(root, "cgroup.controllers"), tt.controllers)
+		diagnoseEmptyV2(root)
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("cgroup.controllers %q logged %q, want %q", tt.controllers, buf.String(), tt.want)
+		}
+		if strings.Contains(buf.String(), "not supported") {
+			t.Errorf("cgroup.controllers %q logged v2 as not supported: %q", tt.controllers, buf.String())
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+// a sink of the stats of each poll
+type Exporter interface {
+	Export(stats []ContainerStats) error
+	// hand over the stats still buffered, called before the collector exits
+	Flush() error
+}
+
+func newExporter(format string) (Exporter, error) {
+	switch format {
+	case "human":
+		return humanExporter{}, nil
+	case "json":
+		return jsonExporter{stream: outputStream}, nil
+	case "influx":
+		// printing the lines can't fall behind
+		if influxUrl == "" {
+			return influxExporter{}, nil
+		}
+		return newQueuedExporter("influx", influxExporter{url: influxUrl, sent: newSentValues()}, sinkQueue), nil
+	case "prometheus":
+		return prometheusExporter{}, nil
+	case "statsd":
+		return newQueuedExporter("statsd", &statsdExporter{addr: statsdAddr, sent: newSentValues()}, sinkQueue), nil
+	}
+	return nil, fmt.Errorf("unknown output %q, must be human, json, influx, prometheus or statsd", format)
+}
+
+// a key=value line per container on stdout
+type humanExporter struct{}
+
+func (humanExporter) Export(stats []ContainerStats) error {
+	for _, s := range stats {
+		if _, err := fmt.Println(s.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fmt writes stdout unbuffered
+func (humanExporter) Flush() error {
+	return nil
+}
+
+// an array per poll, or an object per line with -stream
+type jsonExporter struct {
+	stream bool
+}
+
+func (this jsonExporter) Export(stats []ContainerStats) error {
+	if this.stream {
+		if err := writeNDJSON(os.Stdout, stats); err != nil {
+			return err
+		}
+	} else {
+		out, err := json.Marshal(stats)
+		if err != nil {
+			return fmt.Errorf("failed to encode the stats: %w", err)
+		}
+		if _, err = fmt.Println(string(out)); err != nil {
+			return err
+		}
+	}
+	if ageHistogram {
+		return writeHostStats(os.Stdout)
+	}
+	return nil
+}
+
+// the version of the host objects, apart from the containers' one. They are
+// told from the containers by their host_schema_version.
+const hostSchemaVersion = "1.0"
+
+// the host level stats of a poll, an object on its own line after the
+// containers with -age-histogram
+type HostStats struct {
+	HostSchemaVersion string       `json:"host_schema_version"`
+	Collector         string       `json:"collector,omitempty"`
+	Ages              AgeHistogram `json:"ages"`
+}
+
+func writeHostStats(w io.Writer) error {
+	host := HostStats{HostSchemaVersion: hostSchemaVersion, Collector: collectorId, Ages: lastPollAges()}
+	if err := json.NewEncoder(w).Encode(host); err != nil {
+		return fmt.Errorf("failed to encode the host stats: %w", err)
+	}
+	return nil
+}
+
+func (jsonExporter) Flush() error {
+	return nil
+}
+
+// an object per line and container
+func writeNDJSON(w io.Writer, stats []ContainerStats) error {
+	encoder := json.NewEncoder(w)
+	for _, s := range stats {
+		if err := encoder.Encode(s); err != nil {
+			return fmt.Errorf("failed to encode the stats of %s: %w", s.Id, err)
+		}
+	}
+	return nil
+}
+
+// InfluxDB line protocol on stdout or to -influx-url
+type influxExporter struct {
+	// the -influx-url, empty prints the lines
+	url string
+	// the slow changing values sent to -influx-url, nil sends them all
+	sent *sentValues
+}
+
+func (this influxExporter) Export(stats []ContainerStats) error {
+	return writeInflux(stats, this.url, this.sent)
+}
+
+func (influxExporter) Flush() error {
+	return nil
+}
+
+// the registry in the Prometheus text format on stdout, like for the
+// textfile collector of the node exporter. The registry is fed by Update,
+// the stats aren't needed.
+type prometheusExporter struct{}
+
+func (prometheusExporter) Export(stats []ContainerStats) error {
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather the metrics: %w", err)
+	}
+	for _, f := range families {
+		if _, err = expfmt.MetricFamilyToText(os.Stdout, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (prometheusExporter) Flush() error {
+	return nil
+}
+
+// hands the stats to all the exporters, the first error wins
+type multiExporter []Exporter
+
+func (this multiExporter) Export(stats []ContainerStats) (err error) {
+	for _, e := range this {
+		if eerr := e.Export(stats); eerr != nil && err == nil {
+			err = eerr
+		}
+	}
+	return
+}
+
+func (this multiExporter) Flush() (err error) {
+	for _, e := range this {
+		if ferr := e.Flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return
+}
+
+// drops the stats, for tests
+type nopExporter struct{}
+
+func (nopExporter) Export(stats []ContainerStats) error {
+	return nil
+}
+
+func (nopExporter) Flush() error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+// counts the calls and fails them with err
+type countingExporter struct {
+	exports, flushes int
+	err              error
+}
+
+func (this *countingExporter) Export(stats []ContainerStats) error {
+	this.exports++
+	return this.err
+}
+
+func (this *countingExporter) Flush() error {
+	this.flushes++
+	return this.err
+}
+
+func TestMultiExporter(t *testing.T) {
+	first, second := &countingExporter{err: errors.New("first")}, &countingExporter{err: errors.New("second")}
+	multi := multiExporter{nopExporter{}, first, second}
+	// every exporter gets the stats, the first error is returned
+	if err := multi.Export([]ContainerStats{{Id: "a"}}); err != first.err {
+		t.Errorf("Export = %v, want %v", err, first.err)
+	}
+	if err := multi.Flush(); err != first.err {
+		t.Errorf("Flush = %v, want %v", err, first.err)
+	}
+	if first.exports != 1 || second.exports != 1 || first.flushes != 1 || second.flushes != 1 {
+		t.Errorf("exports %d/%d and flushes %d/%d, want 1 each", first.exports, second.exports, first.flushes, second.flushes)
+	}
+	if err := (multiExporter{nopExporter{}}).Export(nil); err != nil {
+		t.Errorf("nopExporter gave %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+// a cgroup v1 host under t.TempDir(): the subsystem dirs like -cgroup-root
+// finds them and a proc with the cgroups the collector reads through
+// -proc-root. The globals are restored when the test ends, the host memory
+// is read again from the fake meminfo.
+type fakeHost struct {
+	t *testing.T
+	// the -cgroup-root and -proc-root
+	cgroupRoot string
+	procRoot   string
+	// the subsystem dirs below cgroupRoot, cpu and cpuacct co-mounted
+	dirs map[string]string
+}
+
+// the subsystems of the fake host and the dir each is mounted at
+var fakeSubsystems = map[string]string{
+	"cpu":     "cpu,cpuacct",
+	"cpuacct": "cpu,cpuacct",
+	"memory":  "memory",
+	"pids":    "pids",
+}
+
+func newFakeHost(t *testing.T) *fakeHost {
+	t.Helper()
+	root := t.TempDir()
+	host := &fakeHost{
+		t:          t,
+		cgroupRoot: path.Join(root, "cgroup"),
+		procRoot:   path.Join(root, "proc"),
+		dirs:       make(map[string]string),
+	}
+	cgroupsFile := "#subsys_name\thierarchy\tnum_cgroups\tenabled\n"
+	hierarchy := map[string]int{"cpu,cpuacct": 2, "memory": 3, "pids": 4}
+	for name, dir := range fakeSubsystems {
+		host.dirs[name] = path.Join(host.cgroupRoot, dir)
+		host.mkdir(host.dirs[name])
+		cgroupsFile += fmt.Sprintf("%s\t%d\t1\t1\n", name, hierarchy[dir])
+	}
+	host.write(path.Join(host.procRoot, "cgroups"), cgroupsFile)
+	host.write(path.Join(host.cgroupRoot, "online"), "0-1\n")
+
+	savedCgroupRoot, savedProcRoot, savedOnline := cgroupMountRoot, procRoot, onlineCpusFile
+	savedLayouts, savedFiles, savedHostMemory := cgroupLayouts, files, hostMemory
+	cgroupMountRoot, procRoot = host.cgroupRoot, host.procRoot
+	hostMemory = &hostMemoryOnce{}
+	onlineCpusFile = path.Join(host.cgroupRoot, "online")
+	invalidateCgroupsPath()
+	t.Cleanup(func() {
+		cgroupMountRoot, procRoot, onlineCpusFile = savedCgroupRoot, savedProcRoot, savedOnline
+		cgroupLayouts, files, hostMemory = savedLayouts, savedFiles, savedHostMemory
+		invalidateCgroupsPath()
+		containersMutex.Lock()
+		for id, c := range containers {
+			delete(containers, id)
+			deleteContainerMetrics(c)
+		}
+		containersMutex.Unlock()
+	})
+	return host
+}
+
+// a container id made of the digit, like the 64 hex docker ids
+func fakeId(digit string) string {
+	return strings.Repeat(digit, 64)
+}
+
+func (this *fakeHost) mkdir(dir string) {
+	this.t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		this.t.Fatal(err)
+	}
+}
+
+func (this *fakeHost) write(file string, content string) {
+	this.t.Helper()
+	this.mkdir(path.Dir(file))
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		this.t.Fatal(err)
+	}
+}
+
+// the cgroup dir of the container in the subsystem with the cgroupfs driver
+func (this *fakeHost) containerDir(subsystem string, id string) string {
+	return path.Join(this.dirs[subsystem], "docker", id)
+}
+
+// create the cgroup dirs of a container in all the subsystems with empty
+// counters
+func (this *fakeHost) addContainer(id string) {
+	this.t.Helper()
+	for name := range fakeSubsystems {
+		this.mkdir(this.containerDir(name, id))
+	}
+	this.setCpu(id, 0, 0, 0)
+	this.setMemory(id, 0, 0)
+	this.setPids(id, 0, 0)
+}
+
+// create the cgroup dirs of a container with the systemd driver
+func (this *fakeHost) addScope(id string) {
+	this.t.Helper()
+	for name := range fakeSubsystems {
+		this.mkdir(path.Join(this.dirs[name], "system.slice", "docker-"+id+".scope"))
+	}
+}
+
+// write a file in the container's cgroup dir of the subsystem
+func (this *fakeHost) writeCgroup(subsystem string, id string, file string, content string) {
+	this.t.Helper()
+	this.write(path.Join(this.containerDir(subsystem, id), file), content)
+}
+
+// the cumulative CPU usage in ns, split evenly across the two CPUs. user and
+// system are in USER_HZ ticks like in cpuacct.stat.
+func (this *fakeHost) setCpu(id string, total uint64, user uint64, system uint64) {
+	this.t.Helper()
+	this.writeCgroup("cpuacct", id, "cpuacct.usage", fmt.Sprintf("%d\n", total))
+	this.writeCgroup("cpuacct", id, "cpuacct.usage_percpu", fmt.Sprintf("%d %d \n", total/2, total-total/2))
+	this.writeCgroup("cpuacct", id, "cpuacct.stat", fmt.Sprintf("user %d\nsystem %d\n", user, system))
+	this.writeCgroup("cpu", id, "cpu.stat", "nr_periods 0\nnr_throttled 0\nthrottled_time 0\n")
+}
+
+func (this *fakeHost) setMemory(id string, usage uint64, limit uint64) {
+	this.t.Helper()
+	this.writeCgroup("memory", id, "memory.stat", fmt.Sprintf("cache 0\nrss %d\npgfault 0\npgmajfault 0\n", usage))
+	this.writeCgroup("memory", id, "memory.usage_in_bytes", fmt.Sprintf("%d\n", usage))
+	this.writeCgroup("memory", id, "memory.max_usage_in_bytes", fmt.Sprintf("%d\n", usage))
+	this.writeCgroup("memory", id, "memory.failcnt", "0\n")
+	this.writeCgroup("memory", id, "memory.limit_in_bytes", fmt.Sprintf("%d\n", limit))
+}
+
+func (this *fakeHost) setPids(id string, current uint64, max uint64) {
+	this.t.Helper()
+	this.writeCgroup("pids", id, "pids.current", fmt.Sprintf("%d\n", current))
+	limit := "max"
+	if max > 0 {
+		limit = fmt.Sprintf("%d", max)
+	}
+	this.writeCgroup("pids", id, "pids.max", limit+"\n")
+}
+
+// a reader failing the files the fail func picks with err, the others are
+// read from the host
+type failingReader struct {
+	osReader
+	fail func(name string) bool
+	err  error
+}
+
+func (this failingReader) ReadFile(name string) ([]byte, error) {
+	if this.fail(name) {
+		return nil, this.err
+	}
+	return this.osReader.ReadFile(name)
+}
+
+func (this failingReader) ReadDir(name string) ([]os.FileInfo, error) {
+	if this.fail(name) {
+		return nil, this.err
+	}
+	return this.osReader.ReadDir(name)
+}
+
+// a reader of the fixture files by name, the others don't exist
+type fixtureReader struct {
+	osReader
+	files map[string]string
+}
+
+func (this fixtureReader) ReadFile(name string) ([]byte, error) {
+	content, ok := this.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return []byte(content), nil
+}
+
+// swap files for a fixtureReader until the test ends
+func useFixtures(t *testing.T, fixtures map[string]string) {
+	saved := files
+	files = fixtureReader{files: fixtures}
+	t.Cleanup(func() { files = saved })
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the FIFO to read container ids from, set by -fifo. The snapshot of each
+// requested container is written as a JSON line to the FIFO <fifoPath>.reply
+var fifoPath string
+
+type fifoReply struct {
+	Id     string         `json:"id"`
+	Status string         `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Stats  *cgroups.Stats `json:"stats,omitempty"`
+}
+
+func mkfifo(name string) (err error) {
+	err = syscall.Mkfifo(name, 0600)
+	if os.IsExist(err) {
+		return nil
+	}
+	return
+}
+
+func serveFifo(name string) {
+	reply := name + ".reply"
+	for _, f := range []string{name, reply} {
+		if err := mkfifo(f); err != nil {
+			log.Errorf("failed to create fifo %s: %s", f, err.Error())
+			return
+		}
+	}
+	for {
+		// blocks until a writer opens the FIFO, read until it closes it
+		f, err := os.Open(name)
+		if err != nil {
+			log.Errorf("failed to open fifo %s: %s", name, err.Error())
+			return
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			id := strings.TrimSpace(scanner.Text())
+			if id == "" {
+				continue
+			}
+			if err := writeFifoReply(reply, id); err != nil {
+				log.Warnf("failed to reply to fifo %s: %s", reply, err.Error())
+			}
+		}
+		f.Close()
+	}
+}
+
+func writeFifoReply(reply string, id string) (err error) {
+	resp := fifoReply{Id: id}
+	container, ok := lookupContainer(id)
+	if !ok {
+		resp.Status = StatusError
+		resp.Error = "container not tracked"
+	} else {
+		var stats cgroups.Stats
+		var staterr error
+		stats, resp.Status, staterr = container.Stats()
+		if staterr != nil {
+			resp.Error = staterr.Error()
+		}
+		if resp.Status != StatusError {
+			resp.Stats = &stats
+		}
+	}
+
+	// blocks until the requester opens the reply FIFO
+	f, err := os.OpenFile(reply, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(resp)
+}
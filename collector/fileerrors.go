@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// the number of failed reads per cgroup file name, e.g. cpuacct.usage
+var fileReadErrors = make(map[string]uint64)
+var fileReadErrorsChanged bool
+var fileReadErrorsMutex sync.Mutex
+
+// the parse errors of fs.Manager name the file like: ... from Cgroup file "cpu.shares"
+var cgroupFileRe = regexp.MustCompile(`Cgroup file "([^"]+)"`)
+
+// the open errors fs.Manager put in its own message: ... - open /sys/fs/cgroup/pids/docker/<id>/pids.current: ...
+var openFileRe = regexp.MustCompile(`open (/[^:]+): `)
+
+// the name of the cgroup file the error is about, "unknown" if it doesn't tell
+func errorFile(err error) string {
+	if pe, ok := err.(*os.PathError); ok {
+		return path.Base(pe.Path)
+	}
+	if m := cgroupFileRe.FindStringSubmatch(err.Error()); m != nil {
+		return path.Base(m[1])
+	}
+	if m := openFileRe.FindStringSubmatch(err.Error()); m != nil {
+		return path.Base(m[1])
+	}
+	return "unknown"
+}
+
+func countFileError(err error) {
+	file := errorFile(err)
+	collectorFileReadErrors.WithLabelValues(file).Inc()
+	fileReadErrorsMutex.Lock()
+	defer fileReadErrorsMutex.Unlock()
+	fileReadErrors[file]++
+	fileReadErrorsChanged = true
+}
+
+// print the counters if there were new errors since the last time
+func printFileErrors() {
+	fileReadErrorsMutex.Lock()
+	defer fileReadErrorsMutex.Unlock()
+	if !fileReadErrorsChanged {
+		return
+	}
+	fileReadErrorsChanged = false
+	files := make([]string, 0, len(fileReadErrors))
+	for f := range fileReadErrors {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		fmt.Printf("docker_metrics_file_read_errors%s file=%s total=%d\n", collectorLabel(), f, fileReadErrors[f])
+	}
+}
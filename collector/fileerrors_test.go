@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCountFileError(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	if err := os.Remove(path.Join(host.containerDir("pids", id), "pids.current")); err != nil {
+		t.Fatal(err)
+	}
+	before := testutil.ToFloat64(collectorFileReadErrors.WithLabelValues("pids.current"))
+	container.Update(context.Background())
+	if got := testutil.ToFloat64(collectorFileReadErrors.WithLabelValues("pids.current")) - before; got != 1 {
+		t.Errorf("counted %f errors of pids.current, want 1", got)
+	}
+}
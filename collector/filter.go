@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"context"
+	"strings"
+)
+
+// whether the container matches -filter, the container id prefixes or docker
+// names to collect, by id prefix first, then by the docker name which needs
+// the docker API. An empty -filter collects all the containers.
+func matchFilter(ctx context.Context, id string, containerFilter []string) bool {
+	if len(containerFilter) == 0 {
+		return true
+	}
+	for _, f := range containerFilter {
+		if strings.HasPrefix(id, f) {
+			return true
+		}
+	}
+	c, ok := lookupDockerContainer(ctx, id)
+	if !ok {
+		return false
+	}
+	for _, f := range containerFilter {
+		if c.Name() == strings.TrimPrefix(f, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// drop the containers not matching -filter or -label-filter before any
+// Container is built
+func filterContainers(ctx context.Context, list []string) []string {
+	o := options()
+	if len(o.containerFilter) == 0 && len(o.labelFilter) == 0 {
+		return list
+	}
+	matched := make([]string, 0, len(list))
+	for _, id := range list {
+		if matchFilter(ctx, id, o.containerFilter) && matchLabelFilter(ctx, id, o.labelFilter) {
+			matched = append(matched, id)
+		}
+	}
+	return matched
+}
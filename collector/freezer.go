@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"path"
+	"strings"
+)
+
+const (
+	// the container's processes run
+	StateRunning = "RUNNING"
+	// the container is paused, its processes are frozen
+	StateFrozen = "FROZEN"
+)
+
+// read the freezer state of the cgroup, v1 has freezer.state which is
+// FREEZING on the way to FROZEN, v2 has cgroup.freeze which is 1 when frozen
+func readFreezerState(dir string, v2 bool) (state string, err error) {
+	var s string
+	if v2 {
+		s, err = readCgroupString(path.Join(dir, "cgroup.freeze"))
+		if err != nil {
+			return
+		}
+		if s == "1" {
+			return StateFrozen, nil
+		}
+		return StateRunning, nil
+	}
+	s, err = readCgroupString(path.Join(dir, "freezer.state"))
+	if err != nil {
+		return
+	}
+	if strings.HasPrefix(s, "FREEZ") || s == StateFrozen {
+		return StateFrozen, nil
+	}
+	return StateRunning, nil
+}
+
+// the state is RUNNING when there is no freezer to tell
+func (this *Container) UpdateFreezer() {
+	this.state = StateRunning
+	dir, ok := this.controllerPath("freezer")
+	if !ok {
+		return
+	}
+	if state, err := readFreezerState(dir, isCgroupV2(this.cgroupPath)); err == nil {
+		this.state = state
+	}
+}
+
+func (this *Container) Frozen() bool {
+	return this.state == StateFrozen
+}
@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+// the access to /proc and the cgroup files, so the parsers can be fed
+// fixtures instead of the files of the host
+type fsReader interface {
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]os.FileInfo, error)
+	Stat(name string) (os.FileInfo, error)
+	Readlink(name string) (string, error)
+}
+
+// read the files of the host
+type osReader struct{}
+
+func (osReader) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+func (osReader) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(name)
+}
+
+func (osReader) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osReader) Readlink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+// the reader used by all the parsers
+var files fsReader = osReader{}
@@ -0,0 +1,208 @@
+package collector
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative -I statspb statspb/stats.proto
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/konghui/docker-metrics/collector/statspb"
+)
+
+// the address of the gRPC server streaming the stats of each poll, set by
+// -grpc-addr, empty disables it
+var grpcAddr string
+
+func serveGRPC(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %s", addr, err.Error())
+	}
+	server := grpc.NewServer()
+	statspb.RegisterStatsServer(server, statsServer{})
+	log.Infof("serve gRPC on %s", addr)
+	log.Fatal(server.Serve(listener))
+}
+
+type statsServer struct {
+	statspb.UnimplementedStatsServer
+}
+
+// a poll queued for a stream
+type streamedPoll struct {
+	start time.Time
+	stats []ContainerStats
+}
+
+// send a Poll after each poll until the client goes away. A client falling
+// behind drops its oldest polls like the push sinks, the poll isn't held up.
+func (statsServer) StreamStats(req *statspb.StreamStatsRequest, stream statspb.Stats_StreamStatsServer) error {
+	polls := make(chan streamedPoll, options().sinkQueue)
+	unsubscribe := subscribe(func(start time.Time, stats []ContainerStats) {
+		for {
+			select {
+			case polls <- streamedPoll{start, stats}:
+				return
+			default:
+			}
+			select {
+			case <-polls:
+				collectorSinkDropped.WithLabelValues("grpc").Inc()
+			default:
+			}
+		}
+	})
+	defer unsubscribe()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case p := <-polls:
+			if err := stream.Send(pollProto(p.start, p.stats, req.Filter)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// the container matches an id prefix or the name of the filter, all do
+// when it's empty
+func matchStreamFilter(s ContainerStats, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if strings.HasPrefix(s.Id, f) || s.Name == strings.TrimPrefix(f, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// the poll is stamped with its start, a client falling behind gets it later
+// and the time would drift from the samples
+func pollProto(start time.Time, stats []ContainerStats, filter []string) *statspb.Poll {
+	poll := &statspb.Poll{TimeUnixNano: start.UnixNano(), Collector: collectorId}
+	for _, s := range stats {
+		if matchStreamFilter(s, filter) {
+			poll.Containers = append(poll.Containers, statsProto(s))
+		}
+	}
+	return poll
+}
+
+func statsProto(s ContainerStats) *statspb.ContainerStats {
+	p := &statspb.ContainerStats{
+		SchemaVersion:         s.SchemaVersion,
+		Collector:             s.Collector,
+		TimeUnit:              s.TimeUnit,
+		Id:                    s.Id,
+		Name:                  s.Name,
+		ImageDigest:           s.ImageDigest,
+		Status:                s.Status,
+		Shared:                s.Shared,
+		CgroupDepth:           int64(s.CgroupDepth),
+		CgroupPath:            s.CgroupPath,
+		CgroupParent:          s.CgroupParent,
+		AgeSeconds:            s.AgeSeconds,
+		Restarts:              s.Restarts,
+		CollectSeconds:        s.CollectSeconds,
+		State:                 s.State,
+		Ready:                 s.Ready,
+		Error:                 s.Error,
+		Unavailable:           s.Unavailable,
+		SchedPolicy:           s.SchedPolicy,
+		Comm:                  s.Comm,
+		Cmdline:               s.Cmdline,
+		Labels:                s.Labels,
+		CpuUsageTotal:         s.CpuUsageTotal,
+		CpuUserTotal:          s.CpuUserTotal,
+		CpuSystemTotal:        s.CpuSystemTotal,
+		CpuUsage:              s.CpuUsage,
+		CpuPercent:            s.CpuPercent,
+		CpuPercentSmoothed:    s.CpuPercentSmoothed,
+		Cpus:                  int64(s.Cpus),
+		HostCpus:              int64(s.HostCpus),
+		CpuUser:               s.CpuUser,
+		CpuSystem:             s.CpuSystem,
+		CpuUserPercent:        s.CpuUserPercent,
+		CpuSystemPercent:      s.CpuSystemPercent,
+		CpuPercpu:             s.CpuPercpu,
+		CpuAllocatedCores:     s.CpuAllocatedCores,
+		CpuAllocationPercent:  s.CpuAllocationPercent,
+		CpuThrottledPeriods:   s.CpuThrottledPeriods,
+		CpuThrottledTime:      s.CpuThrottledTime,
+		CpuThrottledPercent:   s.CpuThrottledPercent,
+		MemoryUsage:           s.MemoryUsage,
+		MemoryLimit:           s.MemoryLimit,
+		MemoryLimitEffective:  s.MemoryLimitEffective,
+		MemoryUtilization:     s.MemoryUtilization,
+		MemoryFailcnt:         s.MemoryFailcnt,
+		MemoryOom:             s.MemoryOom,
+		MemoryOomKill:         s.MemoryOomKill,
+		MemoryPgfault:         s.MemoryPgfault,
+		MemoryPgmajfault:      s.MemoryPgmajfault,
+		Pids:                  s.Pids,
+		PidsLimit:             s.PidsLimit,
+		PidsUtilization:       s.PidsUtilization,
+		PidsMaxEvents:         s.PidsMaxEvents,
+		BlkioReadBytesPerSec:  s.BlkioReadRate,
+		BlkioWriteBytesPerSec: s.BlkioWriteRate,
+		IoReadBytesPerSec:     s.IoReadRate,
+		IoWriteBytesPerSec:    s.IoWriteRate,
+		IoReadIops:            s.IoReadIosRate,
+		IoWriteIops:           s.IoWriteIosRate,
+		IoDiscardBytesPerSec:  s.IoDiscardRate,
+		IoDiscardIops:         s.IoDiscardIosRate,
+		CpusetCpus:            int64s(s.CpusetCpus),
+		CpusetMems:            int64s(s.CpusetMems),
+		NetRxBytesPerSec:      s.NetRxRate,
+		NetTxBytesPerSec:      s.NetTxRate,
+	}
+	// zero before the first sample like in the JSON
+	if !s.LastUpdate.IsZero() {
+		p.LastUpdateUnixNano = s.LastUpdate.UnixNano()
+	}
+	if s.Nice != nil {
+		nice := int64(*s.Nice)
+		p.Nice = &nice
+	}
+	if len(s.Hugetlb) > 0 {
+		p.Hugetlb = make(map[string]*statspb.HugetlbStats, len(s.Hugetlb))
+		for size, h := range s.Hugetlb {
+			p.Hugetlb[size] = &statspb.HugetlbStats{Usage: h.Usage, MaxUsage: h.MaxUsage, Failcnt: h.Failcnt}
+		}
+	}
+	if len(s.Pressure) > 0 {
+		p.Pressure = make(map[string]*statspb.Pressure, len(s.Pressure))
+		for resource, psi := range s.Pressure {
+			p.Pressure[resource] = &statspb.Pressure{Some: stallProto(psi.Some), Full: stallProto(psi.Full)}
+		}
+	}
+	if len(s.Rdma) > 0 {
+		p.Rdma = make(map[string]*statspb.RdmaUsage, len(s.Rdma))
+		for dev, u := range s.Rdma {
+			p.Rdma[dev] = &statspb.RdmaUsage{HcaHandle: u.HcaHandle, HcaObject: u.HcaObject}
+		}
+	}
+	return p
+}
+
+func stallProto(s PressureStall) *statspb.PressureStall {
+	return &statspb.PressureStall{Avg10: s.Avg10, Avg60: s.Avg60, Avg300: s.Avg300, Total: s.Total}
+}
+
+func int64s(list []int) []int64 {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]int64, len(list))
+	for i, v := range list {
+		out[i] = int64(v)
+	}
+	return out
+}
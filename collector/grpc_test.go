@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/konghui/docker-metrics/collector/statspb"
+)
+
+func TestStreamStats(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	statspb.RegisterStatsServer(server, statsServer{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	dial := func(context.Context, string) (net.Conn, error) { return listener.Dial() }
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dial), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Dial: %s", err)
+	}
+	defer conn.Close()
+	stream, err := statspb.NewStatsClient(conn).StreamStats(ctx, &statspb.StreamStatsRequest{Filter: []string{"web"}})
+	if err != nil {
+		t.Fatalf("StreamStats: %s", err)
+	}
+
+	nice, events := -5, uint64(3)
+	poll := []ContainerStats{
+		{Id: fakeId("a"), Name: "web", Ready: true, CpuPercent: 12.5, Nice: &nice, PidsMaxEvents: &events,
+			CpusetCpus: []int{0, 2}, Rdma: map[string]RdmaUsage{"mlx4_0": {HcaHandle: 2, HcaObject: 2000}}},
+		{Id: fakeId("b"), Name: "db"},
+	}
+	// the server subscribes when the call arrives, publish until it gets one
+	received := make(chan *statspb.Poll, 1)
+	go func() {
+		p, err := stream.Recv()
+		if err != nil {
+			t.Errorf("Recv: %s", err)
+			close(received)
+			return
+		}
+		received <- p
+	}()
+	var got *statspb.Poll
+	start := time.Now().Add(-time.Second)
+	for got == nil {
+		subscribers.publish(start, poll)
+		select {
+		case got = <-received:
+			if got == nil {
+				return
+			}
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("no poll streamed")
+		}
+	}
+
+	if got.TimeUnixNano != start.UnixNano() {
+		t.Errorf("the poll is stamped %d, want its start %d", got.TimeUnixNano, start.UnixNano())
+	}
+	if len(got.Containers) != 1 {
+		t.Fatalf("streamed %d containers, want the one matching the filter", len(got.Containers))
+	}
+	s := got.Containers[0]
+	if s.Id != fakeId("a") || s.Name != "web" || !s.Ready || s.CpuPercent != 12.5 {
+		t.Errorf("streamed %+v", s)
+	}
+	if s.Nice == nil || *s.Nice != -5 || s.PidsMaxEvents == nil || *s.PidsMaxEvents != 3 {
+		t.Errorf("nice %v and pids max events %v, want -5 and 3", s.Nice, s.PidsMaxEvents)
+	}
+	if len(s.CpusetCpus) != 2 || s.CpusetCpus[1] != 2 || s.Rdma["mlx4_0"].GetHcaObject() != 2000 {
+		t.Errorf("cpuset %v and rdma %v", s.CpusetCpus, s.Rdma)
+	}
+	if s.LastUpdateUnixNano != 0 {
+		t.Errorf("last update %d before the first sample, want 0", s.LastUpdateUnixNano)
+	}
+}
@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// the end of the last poll which didn't fail, served by /healthz
+var lastSuccessfulPoll time.Time
+var healthMutex sync.Mutex
+
+func markHealthy(t time.Time) {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	lastSuccessfulPoll = t
+}
+
+// 200 while the last poll succeeded within two intervals, 503 otherwise.
+// With -on-demand the polls follow the scrapes, only the last one counts.
+func serveHealthz(w http.ResponseWriter, r *http.Request) {
+	healthMutex.Lock()
+	last := lastSuccessfulPoll
+	healthMutex.Unlock()
+	if last.IsZero() {
+		http.Error(w, "no successful poll yet", http.StatusServiceUnavailable)
+		return
+	}
+	if age := time.Since(last); age > 2*options().interval && !onDemand {
+		http.Error(w, fmt.Sprintf("last successful poll %s ago", age.Truncate(time.Second)), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
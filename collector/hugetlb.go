@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the huge page usage of the container by page size like 2MB, empty when
+// the hugetlb controller isn't mounted
+func (this *Container) UpdateHugetlb(stat map[string]cgroups.HugetlbStats) {
+	this.hugetlb = nil
+	if len(stat) == 0 {
+		return
+	}
+	this.hugetlb = make(map[string]cgroups.HugetlbStats, len(stat))
+	for size, s := range stat {
+		this.hugetlb[size] = s
+	}
+}
+
+// the page sizes sorted by name
+func (this *Container) hugetlbSizes() []string {
+	sizes := make([]string, 0, len(this.hugetlb))
+	for size := range this.hugetlb {
+		sizes = append(sizes, size)
+	}
+	sort.Strings(sizes)
+	return sizes
+}
+
+func (this *Container) hugetlbLine() (line string) {
+	for _, size := range this.hugetlbSizes() {
+		s := this.hugetlb[size]
+		line += fmt.Sprintf(" hugetlb.%s usage=%d max_usage=%d failcnt=%d", size, s.Usage, s.MaxUsage, s.Failcnt)
+	}
+	return
+}
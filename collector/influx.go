@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// the write endpoint the -output influx lines are POSTed to, like
+// http://localhost:8086/write?db=docker, set by -influx-url. Empty prints them.
+var influxUrl string
+
+var influxClient = &http.Client{Timeout: 5 * time.Second}
+
+// escape the commas, spaces and equal signs of a tag key or value
+var influxTagEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+
+// the stats as InfluxDB line protocol, a line per measurement. Until the
+// container is Ready the delta and rate fields have no baseline and are left
+// out, a 0 would look like an idle container. The slow changing fields are
+// left out when sent says they're unchanged.
+func influxLines(stats []ContainerStats, now time.Time, sent *sentValues) []byte {
+	var buf bytes.Buffer
+	ts := now.UnixNano()
+	sent.poll(now)
+	for _, s := range stats {
+		tags := "id=" + influxTagEscaper.Replace(s.Id)
+		if s.Collector != "" {
+			tags += ",collector=" + influxTagEscaper.Replace(s.Collector)
+		}
+		if s.Name != "" {
+			tags += ",name=" + influxTagEscaper.Replace(s.Name)
+		}
+		if s.ImageDigest != "" {
+			tags += ",image_digest=" + influxTagEscaper.Replace(s.ImageDigest)
+		}
+		for _, k := range s.cgroupSubsystems() {
+			tags += ",cgroup_path_" + labelNameRe.ReplaceAllString(k, "_") + "=" + influxTagEscaper.Replace(s.CgroupPath[k])
+		}
+		// to leave out the duplicates when summing
+		if s.Shared {
+			tags += ",shared=true"
+		}
+		if s.Ready {
+			fmt.Fprintf(&buf, "docker_cpu,%s usage=%g,percent=%g,user=%g,system=%g,user_percent=%g,system_percent=%g,throttled_periods=%di,throttled_time=%g,throttled_percent=%g %d\n",
+				tags, s.CpuUsage, s.CpuPercent, s.CpuUser, s.CpuSystem, s.CpuUserPercent, s.CpuSystemPercent,
+				s.CpuThrottledPeriods, s.CpuThrottledTime, s.CpuThrottledPercent, ts)
+		}
+		memoryFields := fmt.Sprintf("usage=%di", s.MemoryUsage)
+		if sent.send("docker_memory,"+tags+" limit", float64(s.MemoryLimit)) {
+			memoryFields += fmt.Sprintf(",limit=%di", s.MemoryLimit)
+		}
+		if s.MemoryLimitEffective > 0 && sent.send("docker_memory,"+tags+" limit_effective", float64(s.MemoryLimitEffective)) {
+			memoryFields += fmt.Sprintf(",limit_effective=%di", s.MemoryLimitEffective)
+		}
+		if s.Ready {
+			memoryFields += fmt.Sprintf(",failcnt=%di,oom=%di,oom_kill=%di,pgfault=%di,pgmajfault=%di",
+				s.MemoryFailcnt, s.MemoryOom, s.MemoryOomKill, s.MemoryPgfault, s.MemoryPgmajfault)
+		}
+		fmt.Fprintf(&buf, "docker_memory,%s %s %d\n", tags, memoryFields, ts)
+		pidsFields := fmt.Sprintf("current=%di", s.Pids)
+		if sent.send("docker_pids,"+tags+" limit", float64(s.PidsLimit)) {
+			pidsFields += fmt.Sprintf(",limit=%di", s.PidsLimit)
+		}
+		pidsFields += fmt.Sprintf(",utilization=%g", s.PidsUtilization)
+		if s.PidsMaxEvents != nil {
+			pidsFields += fmt.Sprintf(",max_events=%di", *s.PidsMaxEvents)
+		}
+		fmt.Fprintf(&buf, "docker_pids,%s %s %d\n", tags, pidsFields, ts)
+		if s.CgroupDepth > 0 && sent.send("docker_cgroup,"+tags+" depth", float64(s.CgroupDepth)) {
+			fmt.Fprintf(&buf, "docker_cgroup,%s depth=%di %d\n", tags, s.CgroupDepth, ts)
+		}
+		for _, dev := range s.rdmaDevices() {
+			usage := s.Rdma[dev]
+			fmt.Fprintf(&buf, "docker_rdma,%s,device=%s hca_handle=%di,hca_object=%di %d\n", tags, influxTagEscaper.Replace(dev), usage.HcaHandle, usage.HcaObject, ts)
+		}
+		if s.SchedPolicy != "" {
+			sched := tags + ",policy=" + influxTagEscaper.Replace(s.SchedPolicy)
+			if sent.send("docker_sched,"+sched+" nice", float64(*s.Nice)) {
+				fmt.Fprintf(&buf, "docker_sched,%s nice=%di %d\n", sched, *s.Nice, ts)
+			}
+		}
+		if s.Ready {
+			fmt.Fprintf(&buf, "docker_blkio,%s read_bytes_per_sec=%g,write_bytes_per_sec=%g %d\n", tags, s.BlkioReadRate, s.BlkioWriteRate, ts)
+			fmt.Fprintf(&buf, "docker_net,%s rx_bytes_per_sec=%g,tx_bytes_per_sec=%g %d\n", tags, s.NetRxRate, s.NetTxRate, ts)
+		}
+	}
+	return buf.Bytes()
+}
+
+// print the lines or POST them to the url
+func writeInflux(stats []ContainerStats, url string, sent *sentValues) error {
+	lines := influxLines(stats, time.Now(), sent)
+	if url == "" {
+		_, err := os.Stdout.Write(lines)
+		return err
+	}
+	resp, err := influxClient.Post(url, "text/plain; charset=utf-8", bytes.NewReader(lines))
+	if err != nil {
+		return fmt.Errorf("failed to write to influx %s: %w", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to write to influx %s: %s", url, resp.Status)
+	}
+	return nil
+}
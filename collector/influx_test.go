@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInfluxLines(t *testing.T) {
+	now := time.Unix(1, 0)
+	stats := []ContainerStats{
+		{Id: "a", Name: "web 1", Ready: true, CpuUsage: 5, CpuPercent: 2.5, MemoryUsage: 10, MemoryLimit: 20, Pids: 3, NetRxRate: 7},
+		{Id: "b", MemoryUsage: 10, Pids: 1},
+	}
+	got := string(influxLines(stats, now, nil))
+	want := `docker_cpu,id=a,name=web\ 1 usage=5,percent=2.5,user=0,system=0,user_percent=0,system_percent=0,throttled_periods=0i,throttled_time=0,throttled_percent=0 1000000000
+docker_memory,id=a,name=web\ 1 usage=10i,limit=20i,failcnt=0i,oom=0i,oom_kill=0i,pgfault=0i,pgmajfault=0i 1000000000
+docker_pids,id=a,name=web\ 1 current=3i,limit=0i,utilization=0 1000000000
+docker_blkio,id=a,name=web\ 1 read_bytes_per_sec=0,write_bytes_per_sec=0 1000000000
+docker_net,id=a,name=web\ 1 rx_bytes_per_sec=7,tx_bytes_per_sec=0 1000000000
+docker_memory,id=b usage=10i,limit=0i 1000000000
+docker_pids,id=b current=1i,limit=0i,utilization=0 1000000000
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	// the container warming up has no delta or rate field
+	for _, line := range strings.Split(got, "\n") {
+		if strings.Contains(line, "id=b ") && (strings.Contains(line, "percent") || strings.Contains(line, "per_sec") || strings.Contains(line, "oom")) {
+			t.Errorf("a delta of the container warming up: %s", line)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the block I/O of a v2 container from io.stat, summed over the devices. The
+// v1 blkio files don't count the operations.
+type IoInfo struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadIos    uint64
+	WriteIos   uint64
+	// discarded like by fstrim, the kernel counts them apart from the writes
+	DiscardBytes uint64
+	DiscardIos   uint64
+	// per second since the previous sample
+	ReadBytesRate    float64
+	WriteBytesRate   float64
+	ReadIosRate      float64
+	WriteIosRate     float64
+	DiscardBytesRate float64
+	DiscardIosRate   float64
+	sampled          bool
+}
+
+// read io.stat, it contains a line per device of the form:
+//
+// 8:0 rbytes=90112 wbytes=0 rios=3 wios=0 dbytes=0 dios=0
+func readIoStat(dir string) (info IoInfo, err error) {
+	var out []byte
+	out, err = files.ReadFile(path.Join(dir, "io.stat"))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return info, fmt.Errorf("failed to parse io.stat entry %s", line)
+			}
+			var v uint64
+			v, err = strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return info, fmt.Errorf("failed to parse io.stat entry %s: %s", line, err.Error())
+			}
+			switch kv[0] {
+			case "rbytes":
+				info.ReadBytes += v
+			case "wbytes":
+				info.WriteBytes += v
+			case "rios":
+				info.ReadIos += v
+			case "wios":
+				info.WriteIos += v
+			case "dbytes":
+				info.DiscardBytes += v
+			case "dios":
+				info.DiscardIos += v
+			}
+		}
+	}
+	return
+}
+
+func (this *IoInfo) counters() counters {
+	return counters{
+		"rbytes": this.ReadBytes,
+		"wbytes": this.WriteBytes,
+		"rios":   this.ReadIos,
+		"wios":   this.WriteIos,
+		"dbytes": this.DiscardBytes,
+		"dios":   this.DiscardIos,
+	}
+}
+
+func (this *Container) UpdateIo() {
+	if !isCgroupV2(this.cgroupPath) {
+		return
+	}
+	dir := this.cgroupPath[unifiedSubsystem]
+	info, err := readIoStat(dir)
+	if err != nil {
+		this.logger.WithFields(log.Fields{
+			"subsystem": "io",
+			"error":     err.Error(),
+		}).Debug("failed to read io.stat")
+		this.io = IoInfo{}
+		return
+	}
+	info.sampled = true
+	previous := this.io
+	this.io = info
+	if !previous.sampled || this.previousSampled.IsZero() {
+		return
+	}
+	rates := counterRates(info.counters(), previous.counters(), this.sampled.Sub(this.previousSampled))
+	this.io.ReadBytesRate = rates["rbytes"]
+	this.io.WriteBytesRate = rates["wbytes"]
+	this.io.ReadIosRate = rates["rios"]
+	this.io.WriteIosRate = rates["wios"]
+	this.io.DiscardBytesRate = rates["dbytes"]
+	this.io.DiscardIosRate = rates["dios"]
+}
@@ -0,0 +1,32 @@
+package collector
+
+import (
+	"path"
+	"testing"
+)
+
+func TestReadIoStat(t *testing.T) {
+	host := newFakeHost(t)
+	dir := path.Join(host.cgroupRoot, "io")
+	host.write(path.Join(dir, "io.stat"), "8:0 rbytes=90112 wbytes=4096 rios=3 wios=1 dbytes=0 dios=0\n"+
+		"8:16 rbytes=10 wbytes=20 rios=1 wios=2 dbytes=512 dios=1\n\n")
+	info, err := readIoStat(dir)
+	if err != nil {
+		t.Fatalf("readIoStat: %s", err)
+	}
+	want := IoInfo{ReadBytes: 90122, WriteBytes: 4116, ReadIos: 4, WriteIos: 3, DiscardBytes: 512, DiscardIos: 1}
+	if info != want {
+		t.Errorf("got %+v, want %+v", info, want)
+	}
+}
+
+func TestReadIoStatMalformed(t *testing.T) {
+	host := newFakeHost(t)
+	dir := path.Join(host.cgroupRoot, "io")
+	for _, content := range []string{"8:0 rbytes\n", "8:0 rbytes=x\n", "8:0 rbytes=-1\n"} {
+		host.write(path.Join(dir, "io.stat"), content)
+		if _, err := readIoStat(dir); err == nil {
+			t.Errorf("readIoStat(%q) gave no error", content)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the docker labels exported with the stats, set by -export-labels
+var exportLabels []string
+
+func parseLabelFilter(list []string) (filter map[string]string, err error) {
+	filter = make(map[string]string)
+	for _, kv := range list {
+		i := strings.Index(kv, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("label filter %q must be key=value", kv)
+		}
+		filter[kv[:i]] = kv[i+1:]
+	}
+	return
+}
+
+// whether the docker labels of the container match -label-filter, like
+// com.company.team=payments. All of them must match.
+func matchLabelFilter(ctx context.Context, id string, labelFilter map[string]string) bool {
+	if len(labelFilter) == 0 {
+		return true
+	}
+	c, ok := lookupDockerContainer(ctx, id)
+	if !ok {
+		return false
+	}
+	for k, v := range labelFilter {
+		if got, ok := c.Labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// the values of the -export-labels of the container from the docker API
+func readDockerLabels(ctx context.Context, id string) (labels map[string]string) {
+	labels = make(map[string]string)
+	c, ok := lookupDockerContainer(ctx, id)
+	if !ok {
+		return
+	}
+	for _, k := range exportLabels {
+		if v, ok := c.Labels[k]; ok {
+			labels[k] = v
+		}
+	}
+	return
+}
+
+// the label keys which aren't valid prometheus label names, like the dots
+// of com.company.team
+var labelNameRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// the prometheus labels of the exported xattr and docker labels, the
+// metrics only carry container_id so they're joined on it
+var containerInfoLabels []string
+var containerInfo *prometheus.GaugeVec
+
+// register docker_container_labels with a label per exported key, the keys
+// are only known after the flags are parsed. A key both an xattr and a docker
+// label is exported once, two keys with the same label name are an error.
+func registerLabelsMetric() (err error) {
+	keys := make([]string, 0, len(xattrLabels)+len(exportLabels))
+	names := append([]string{}, containerLabels...)
+	byName := make(map[string]string)
+	for _, k := range append(append([]string{}, xattrLabels...), exportLabels...) {
+		name := "label_" + labelNameRe.ReplaceAllString(k, "_")
+		if other, ok := byName[name]; ok {
+			if other == k {
+				continue
+			}
+			return fmt.Errorf("the labels %s and %s are both exported as %s", other, k, name)
+		}
+		byName[name] = k
+		keys = append(keys, k)
+		names = append(names, name)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	containerInfoLabels = keys
+	containerInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_labels",
+		Help: "Always 1, the exported labels of the container.",
+	}, names)
+	registerer.MustRegister(containerInfo)
+	return
+}
+
+func (this *Container) labelValues() []string {
+	values := []string{this.id}
+	for _, k := range containerInfoLabels {
+		values = append(values, this.labels[k])
+	}
+	return values
+}
@@ -0,0 +1,32 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterLabelsMetric(t *testing.T) {
+	defer func(xattr, export []string) {
+		xattrLabels, exportLabels = xattr, export
+		if containerInfo != nil {
+			registerer.Unregister(containerInfo)
+		}
+		containerInfo, containerInfoLabels = nil, nil
+	}(xattrLabels, exportLabels)
+
+	// the key in both lists is exported once
+	xattrLabels, exportLabels = []string{"team"}, []string{"team", "com.company.app"}
+	if err := registerLabelsMetric(); err != nil {
+		t.Fatalf("registerLabelsMetric: %s", err)
+	}
+	if want := []string{"team", "com.company.app"}; !reflect.DeepEqual(containerInfoLabels, want) {
+		t.Errorf("labels = %v, want %v", containerInfoLabels, want)
+	}
+	registerer.Unregister(containerInfo)
+
+	// the keys differ but not their label names
+	xattrLabels, exportLabels = nil, []string{"com.company.app", "com_company.app"}
+	if err := registerLabelsMetric(); err == nil {
+		t.Error("no error for two keys exported as label_com_company_app")
+	}
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"context"
+	"errors"
+)
+
+// the options of a Collector embedded in another program, the zero value
+// reads the host like the command line defaults
+type Options struct {
+	// where proc and the cgroup hierarchy are mounted, like /host/proc and
+	// /host/sys/fs/cgroup, empty reads /proc and the mount points
+	ProcRoot   string
+	CgroupRoot string
+	// the container id prefixes or names to collect, empty collects all
+	Filter []string
+}
+
+// the collection of the containers for a program embedding it. The
+// containers, their previous samples and the options are kept by the
+// package, so there can only be one Collector per process.
+type Collector struct{}
+
+// New was already called in this process
+var errCollectorExists = errors.New("a Collector already exists in this process")
+
+// the Collector was created, under the pollMutex
+var collectorCreated bool
+
+// set up the collection with the options, a second call returns
+// errCollectorExists
+func New(opts Options) (*Collector, error) {
+	pollMutex.Lock()
+	defer pollMutex.Unlock()
+	if collectorCreated {
+		return nil, errCollectorExists
+	}
+	collectorCreated = true
+	procRoot = opts.ProcRoot
+	if procRoot == "" {
+		procRoot = "/proc"
+	}
+	cgroupMountRoot = opts.CgroupRoot
+	o := options()
+	o.containerFilter = opts.Filter
+	setOptions(o)
+	invalidateCgroupsPath()
+	return &Collector{}, nil
+}
+
+// poll the containers and return their stats, a poll failing all of them
+// returns their stats with the error. The deltas like the CPU
+// percentage are against the previous Snapshot, the containers aren't Ready
+// before they have one. The polls are serialized with the -on-demand scrapes,
+// a poll is aborted after the -interval.
+func (this *Collector) Snapshot() ([]ContainerStats, error) {
+	pollMutex.Lock()
+	defer pollMutex.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), options().interval)
+	defer cancel()
+	result, err := collect(ctx)
+	return result.stats, err
+}
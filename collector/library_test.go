@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+// the Collector of the fake host, the next test can create its own
+func newTestCollector(t *testing.T, host *fakeHost) *Collector {
+	t.Helper()
+	c, err := New(Options{ProcRoot: host.procRoot, CgroupRoot: host.cgroupRoot})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	t.Cleanup(func() {
+		pollMutex.Lock()
+		collectorCreated = false
+		pollMutex.Unlock()
+	})
+	return c
+}
+
+func TestNewOnlyOnce(t *testing.T) {
+	host := newFakeHost(t)
+	newTestCollector(t, host)
+	if _, err := New(Options{}); !errors.Is(err, errCollectorExists) {
+		t.Errorf("the second New gave %v, want errCollectorExists", err)
+	}
+	if procRoot != host.procRoot {
+		t.Errorf("the second New changed the proc root to %s", procRoot)
+	}
+}
+
+func TestNewDefaultProcRoot(t *testing.T) {
+	host := newFakeHost(t)
+	newTestCollector(t, host)
+	pollMutex.Lock()
+	collectorCreated = false
+	pollMutex.Unlock()
+	if _, err := New(Options{CgroupRoot: host.cgroupRoot}); err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	if procRoot != "/proc" {
+		t.Errorf("the proc root is %s, want /proc without Options.ProcRoot", procRoot)
+	}
+}
+
+func TestCollectorSnapshot(t *testing.T) {
+	host := newFakeHost(t)
+	ids := []string{fakeId("1"), fakeId("2")}
+	for _, id := range ids {
+		host.addContainer(id)
+	}
+	c := newTestCollector(t, host)
+
+	first, err := c.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+	if len(first) != 2 || first[0].Ready || first[1].Ready {
+		t.Fatalf("first snapshot %+v, want the 2 containers without a baseline", first)
+	}
+	for _, id := range ids {
+		host.setCpu(id, 1000000, 0, 0)
+	}
+	second, err := c.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+	seen := make(map[string]bool)
+	for _, s := range second {
+		seen[s.Id] = true
+		if !s.Ready || s.CpuPercent <= 0 {
+			t.Errorf("container %s ready %t cpu %f, want the delta against the first snapshot", s.Id, s.Ready, s.CpuPercent)
+		}
+	}
+	if len(second) != 2 || !seen[ids[0]] || !seen[ids[1]] {
+		t.Errorf("second snapshot has %v, want %v", seen, ids)
+	}
+}
@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// identical warnings within this long are logged once, set by -log-dedup-window.
+// 0 logs all of them.
+var logDedupWindow = time.Minute
+
+// suppresses the same collection error of many containers or polls, and logs
+// how many were suppressed once the window is over
+type logDeduper struct {
+	mutex sync.Mutex
+	seen  map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	msg        string
+	err        string
+	first      time.Time
+	suppressed int
+}
+
+var warnDeduper = &logDeduper{seen: make(map[string]*dedupEntry)}
+
+// the container ids are masked, so the same failure of several containers
+// counts as one warning
+func dedupKey(msg string, err error) (key string, text string) {
+	text = containerIdRe.ReplaceAllString(err.Error(), "<id>")
+	return msg + "\x00" + text, text
+}
+
+// the warning should be logged, it was not seen within the window
+func (this *logDeduper) allow(msg string, err error) bool {
+	if logDedupWindow <= 0 {
+		return true
+	}
+	key, text := dedupKey(msg, err)
+	now := time.Now()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	e, ok := this.seen[key]
+	if ok && now.Sub(e.first) < logDedupWindow {
+		e.suppressed++
+		return false
+	}
+	if ok {
+		e.summarize()
+	}
+	this.seen[key] = &dedupEntry{msg: msg, err: text, first: now}
+	return true
+}
+
+// log the summaries of the windows which are over, called after each poll
+func (this *logDeduper) flush() {
+	now := time.Now()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for key, e := range this.seen {
+		if now.Sub(e.first) < logDedupWindow {
+			continue
+		}
+		e.summarize()
+		delete(this.seen, key)
+	}
+}
+
+func (this *dedupEntry) summarize() {
+	if this.suppressed == 0 {
+		return
+	}
+	log.WithFields(log.Fields{
+		"error":      this.err,
+		"suppressed": this.suppressed,
+	}).Warnf("%s: %d occurrences suppressed in the last %s", this.msg, this.suppressed, logDedupWindow)
+}
@@ -0,0 +1,122 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the kernel reports no memory limit as the largest int64 aligned to its
+// page size: 9223372036854771712 (0x7FFFFFFFFFFFF000) with 4k pages,
+// 0x7FFFFFFFFFFF0000 with 64k pages, or unaligned 9223372036854775807 on old
+// kernels. v2 writes "max", which readCgroupMax turns into MaxUint64.
+const memoryUnlimited uint64 = 0x7FFFFFFFFFFF0000
+
+// the limit is one of the no limit sentinels
+func isUnlimited(v uint64) bool {
+	return v >= memoryUnlimited
+}
+
+// 0 means unlimited, so dashboards don't show the huge sentinel
+func normalizeMemoryLimit(limit uint64) uint64 {
+	if isUnlimited(limit) {
+		return 0
+	}
+	return limit
+}
+
+// the memory usage of the container in bytes
+type MemoryInfo struct {
+	Usage uint64
+	// 0 when there is no limit
+	Limit uint64
+	Cache uint64
+	Rss   uint64
+	Swap  uint64
+	// the usage relative to the limit, the effective limit of the parents or
+	// the host memory when the container has none
+	Utilization float64
+}
+
+// the MemTotal of /proc/meminfo in bytes
+func readMemTotal() (total uint64, err error) {
+	var out []byte
+	out, err = files.ReadFile(procPath("meminfo"))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// MemTotal:       16318440 kB
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != "MemTotal:" || fields[2] != "kB" {
+			continue
+		}
+		if total, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return 0, fmt.Errorf("failed to parse meminfo entry %s: %s", line, err.Error())
+		}
+		return total * 1024, nil
+	}
+	return 0, fmt.Errorf("no MemTotal in meminfo")
+}
+
+// the host memory is read once, it only changes on memory hotplug. The
+// tests swap it for a new one with the meminfo of their -proc-root.
+type hostMemoryOnce struct {
+	sync.Once
+	total uint64
+}
+
+var hostMemory = &hostMemoryOnce{}
+
+// the memory of the host in bytes, 0 if meminfo can't be read
+func hostMemoryTotal() uint64 {
+	hostMemory.Do(func() {
+		var err error
+		if hostMemory.total, err = readMemTotal(); err != nil {
+			log.Warnf("failed to read the host memory: %s", err.Error())
+		}
+	})
+	return hostMemory.total
+}
+
+// the share of the memory limit in use, after UpdateMemory and
+// UpdateMemoryLimit. A container without a limit of its own is bound by the
+// limit of a parent, else by the host memory.
+func (this *Container) UpdateMemoryUtilization() {
+	this.memory.Utilization = 0
+	limit := this.memory.Limit
+	if limit == 0 && this.hasMemoryLimit {
+		limit = this.memoryLimit
+	}
+	if limit == 0 {
+		limit = hostMemoryTotal()
+	}
+	if limit > 0 {
+		this.memory.Utilization = float64(this.memory.Usage) / float64(limit)
+	}
+}
+
+func (this *Container) UpdateMemory(stat cgroups.MemoryStats) {
+	this.memory = MemoryInfo{
+		Usage: stat.Usage.Usage,
+		Limit: normalizeMemoryLimit(stat.Usage.Limit),
+		Cache: stat.Cache,
+	}
+	if isCgroupV2(this.cgroupPath) {
+		this.memory.Rss = stat.Stats["anon"]
+		this.memory.Swap = stat.SwapUsage.Usage
+		return
+	}
+	this.memory.Rss = stat.Stats["rss"]
+	// v1 accounts memory+swap together in memory.memsw.usage_in_bytes, the
+	// swap is what it has above the memory usage. Both are read one after
+	// the other, a usage grown in between would make it negative.
+	memsw, usage := stat.SwapUsage.Usage, stat.Usage.Usage
+	if memsw > usage {
+		this.memory.Swap = memsw - usage
+	}
+}
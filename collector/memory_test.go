@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"testing"
+)
+
+func TestIsUnlimited(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     uint64
+		unlimited bool
+	}{
+		{"4k pages", 0x7FFFFFFFFFFFF000, true},
+		{"64k pages", 0x7FFFFFFFFFFF0000, true},
+		{"old kernels", 0x7FFFFFFFFFFFFFFF, true},
+		{"v2 max", math.MaxUint64, true},
+		{"a real limit just below", 0x7FFFFFFFFFFF0000 - 4096, false},
+		{"a real limit", 8 << 30, false},
+	}
+	for _, test := range tests {
+		if got := isUnlimited(test.limit); got != test.unlimited {
+			t.Errorf("%s: isUnlimited(%#x) = %v, want %v", test.name, test.limit, got, test.unlimited)
+		}
+		want := test.limit
+		if test.unlimited {
+			want = 0
+		}
+		if got := normalizeMemoryLimit(test.limit); got != want {
+			t.Errorf("%s: normalizeMemoryLimit(%#x) = %d, want %d", test.name, test.limit, got, want)
+		}
+	}
+}
+
+func TestUpdateMemoryUtilization(t *testing.T) {
+	const unlimited = 0x7FFFFFFFFFFFF000
+	tests := []struct {
+		name string
+		// the limit of the container and of its docker parent, 0 for none
+		limit       uint64
+		parentLimit uint64
+		want        float64
+	}{
+		{name: "the container's own limit", limit: 4 << 30, want: 0.25},
+		{name: "the effective limit of a parent", limit: unlimited, parentLimit: 2 << 30, want: 0.5},
+		{name: "the host memory", limit: unlimited, want: 0.125},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host := newFakeHost(t)
+			// 8GiB
+			host.write(path.Join(host.procRoot, "meminfo"), "MemTotal:        8388608 kB\nMemFree:         1048576 kB\n")
+			id := fakeId("a")
+			host.addContainer(id)
+			if test.parentLimit > 0 {
+				host.write(path.Join(host.dirs["memory"], "docker", "memory.limit_in_bytes"), fmt.Sprintf("%d\n", test.parentLimit))
+			}
+			container := sampledContainer(t, host, id, func() { host.setMemory(id, 1<<30, test.limit) })
+			if got := container.memory.Utilization; got != test.want {
+				t.Errorf("utilization = %g, want %g", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,738 @@
+package collector
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// the registry of the metrics served on /metrics
+var registry = prometheus.NewRegistry()
+
+var containerLabels = []string{"container_id"}
+
+var (
+	cpuUsageTotal = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_usage_total",
+		Help: "Cumulative CPU time consumed by the container in nanoseconds.",
+	}, containerLabels)
+	cpuUserTotal = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_user_total",
+		Help: "Cumulative CPU time consumed by the container in user mode in nanoseconds.",
+	}, containerLabels)
+	cpuSystemTotal = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_system_total",
+		Help: "Cumulative CPU time consumed by the container in kernel mode in nanoseconds.",
+	}, containerLabels)
+	cpuPercent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_percent",
+		Help: "Percentage of the host's CPU capacity used by the container since the previous sample.",
+	}, containerLabels)
+	cpuPercentSmoothed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_percent_smoothed",
+		Help: "Moving average of docker_cpu_percent, exported with -cpu-smoothing.",
+	}, containerLabels)
+	cpuCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_count",
+		Help: "CPUs the CPU percentage of the container is relative to, its cpuset or the online host CPUs.",
+	}, containerLabels)
+	cpuAllocatedCores = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_allocated_cores",
+		Help: "Cores the CFS quota of the container allows, the host's cores if unlimited.",
+	}, containerLabels)
+	cpuAllocationPercent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_allocation_percent",
+		Help: "Percentage of the allocated cores used by the container since the previous sample.",
+	}, containerLabels)
+	cpuThrottledPeriods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_throttled_periods",
+		Help: "CFS periods the container was throttled in since the previous sample.",
+	}, containerLabels)
+	cpuThrottledSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_throttled_seconds",
+		Help: "Time the container was throttled for since the previous sample in seconds.",
+	}, containerLabels)
+	cpuThrottledPercent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cpu_throttled_percent",
+		Help: "Percentage of the CFS periods the container was throttled in since the previous sample.",
+	}, containerLabels)
+	containerAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_age_seconds",
+		Help: "Time since the collector first saw the container or its restart in seconds.",
+	}, containerLabels)
+	containerLastUpdate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_last_update_seconds",
+		Help: "Unix time of the last successful sample of the container.",
+	}, containerLabels)
+	containerRestarts = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_container_restarts_total",
+		Help: "Number of restarts of the container seen since the collector discovered it.",
+	}, containerLabels)
+	containerCollectSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_collect_seconds",
+		Help: "Time the last collection of the container took in seconds.",
+	}, containerLabels)
+	containerUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_up",
+		Help: "1 if the last collection of the container got stats, 0 if it failed.",
+	}, containerLabels)
+	cgroupDepthGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_cgroup_depth",
+		Help: "Dirs of the cgroup of the container below the subsystem mount point, exported with -cgroup-depth.",
+	}, containerLabels)
+	containerShared = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_shared",
+		Help: "1 if another container has the same cgroups and so the same stats, 0 otherwise.",
+	}, containerLabels)
+	containerNice = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_nice",
+		Help: "Nice value of the first process of the container, exported with -sched.",
+	}, containerLabels)
+	containerFrozen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_container_frozen",
+		Help: "1 if the container is paused by the freezer, 0 otherwise.",
+	}, containerLabels)
+	memoryUsageBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_usage_bytes",
+		Help: "Memory used by the container in bytes.",
+	}, containerLabels)
+	memoryLimitBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_limit_bytes",
+		Help: "Memory limit of the container in bytes, 0 if unlimited.",
+	}, containerLabels)
+	memoryLimitEffectiveBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_limit_effective_bytes",
+		Help: "Smallest memory limit of the cgroup of the container and its parents in bytes, 0 if unlimited.",
+	}, containerLabels)
+	memoryUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_utilization",
+		Help: "Memory usage of the container relative to its limit, the limit of its parents or the host memory.",
+	}, containerLabels)
+	memoryCacheBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_cache_bytes",
+		Help: "Page cache memory of the container in bytes.",
+	}, containerLabels)
+	memoryRssBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_rss_bytes",
+		Help: "Anonymous memory of the container in bytes.",
+	}, containerLabels)
+	memorySwapBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_memory_swap_bytes",
+		Help: "Swap used by the container in bytes.",
+	}, containerLabels)
+	memoryFailcnt = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_memory_failcnt_total",
+		Help: "Cumulative number of times the memory usage of the container hit its limit.",
+	}, containerLabels)
+	memoryOom = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_memory_oom_total",
+		Help: "Cumulative number of times the OOM killer was invoked in the container, cgroup v2 only.",
+	}, containerLabels)
+	memoryOomKill = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_memory_oom_kill_total",
+		Help: "Cumulative number of processes of the container killed by the OOM killer.",
+	}, containerLabels)
+	memoryPgfault = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_memory_pgfault_total",
+		Help: "Cumulative number of page faults of the container.",
+	}, containerLabels)
+	memoryPgmajfault = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_memory_pgmajfault_total",
+		Help: "Cumulative number of major page faults of the container, which needed a read from disk.",
+	}, containerLabels)
+	blkioReadBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_blkio_read_bytes_total",
+		Help: "Cumulative bytes read by the container from all block devices.",
+	}, containerLabels)
+	blkioWriteBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_blkio_write_bytes_total",
+		Help: "Cumulative bytes written by the container to all block devices.",
+	}, containerLabels)
+	ioReadBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_io_read_bytes_total",
+		Help: "Cumulative bytes read by the container from all block devices, from the cgroup v2 io.stat.",
+	}, containerLabels)
+	ioWriteBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_io_write_bytes_total",
+		Help: "Cumulative bytes written by the container to all block devices, from the cgroup v2 io.stat.",
+	}, containerLabels)
+	ioReadIos = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_io_read_ios_total",
+		Help: "Cumulative read operations of the container on all block devices, from the cgroup v2 io.stat.",
+	}, containerLabels)
+	ioWriteIos = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_io_write_ios_total",
+		Help: "Cumulative write operations of the container on all block devices, from the cgroup v2 io.stat.",
+	}, containerLabels)
+	ioDiscardBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_io_discard_bytes_total",
+		Help: "Cumulative bytes discarded by the container on all block devices, from the cgroup v2 io.stat.",
+	}, containerLabels)
+	ioDiscardIos = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_io_discard_ios_total",
+		Help: "Cumulative discard operations of the container on all block devices, from the cgroup v2 io.stat.",
+	}, containerLabels)
+	netRxBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_net_rx_bytes_total",
+		Help: "Cumulative bytes received by the container on all interfaces but lo.",
+	}, containerLabels)
+	netTxBytes = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_net_tx_bytes_total",
+		Help: "Cumulative bytes sent by the container on all interfaces but lo.",
+	}, containerLabels)
+	pidsCurrent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_pids_current",
+		Help: "Number of processes and threads in the container.",
+	}, containerLabels)
+	pidsLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_pids_limit",
+		Help: "Maximum number of processes and threads in the container, 0 if unlimited.",
+	}, containerLabels)
+	pidsUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_pids_utilization",
+		Help: "Ratio of the processes and threads to the pids limit, 0 if unlimited.",
+	}, containerLabels)
+	pidsMaxEvents = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_pids_max_events_total",
+		Help: "Cumulative number of forks of the container which failed on the pids limit, cgroup v2 only.",
+	}, containerLabels)
+)
+
+// a series of a container, removed when the container is gone
+type containerMetric interface {
+	prometheus.Collector
+	DeleteLabelValues(lvs ...string) bool
+}
+
+// all the per container metrics, to remove the series of a gone container
+var containerMetrics = []containerMetric{
+	cpuUsageTotal,
+	cpuUserTotal,
+	cpuSystemTotal,
+	cpuPercent,
+	cpuPercentSmoothed,
+	cpuCount,
+	cpuAllocatedCores,
+	cpuAllocationPercent,
+	cpuThrottledPeriods,
+	cpuThrottledSeconds,
+	cpuThrottledPercent,
+	containerAge,
+	containerLastUpdate,
+	containerRestarts,
+	containerUp,
+	containerCollectSeconds,
+	cgroupDepthGauge,
+	containerShared,
+	containerNice,
+	containerFrozen,
+	memoryUsageBytes,
+	memoryLimitBytes,
+	memoryLimitEffectiveBytes,
+	memoryUtilization,
+	memoryCacheBytes,
+	memoryRssBytes,
+	memorySwapBytes,
+	memoryFailcnt,
+	memoryOom,
+	memoryOomKill,
+	memoryPgfault,
+	memoryPgmajfault,
+	blkioReadBytes,
+	blkioWriteBytes,
+	ioReadBytes,
+	ioWriteBytes,
+	ioReadIos,
+	ioWriteIos,
+	ioDiscardBytes,
+	ioDiscardIos,
+	netRxBytes,
+	netTxBytes,
+	pidsCurrent,
+	pidsLimit,
+	pidsUtilization,
+	pidsMaxEvents,
+}
+
+// one series per core, kept apart from containerMetrics as it has the cpu label
+var cpuPercpuUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "docker_cpu_percpu_usage",
+	Help: "CPU time consumed by the container on the core since the previous sample in nanoseconds.",
+}, []string{"container_id", "cpu"})
+
+// the image digest as a label, kept apart from containerMetrics as it has
+// the image_digest label
+var containerImage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "docker_container_image_info",
+	Help: "Always 1, the digest of the image of the container, exported with -image-digest.",
+}, []string{"container_id", "image_digest"})
+
+// the cgroup path of each subsystem as a label, kept apart from
+// containerMetrics as it has the subsystem and cgroup_path labels
+var containerCgroup = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "docker_container_cgroup_info",
+	Help: "Always 1, the cgroup path of the container in the subsystem, exported with -cgroup-path-label.",
+}, []string{"container_id", "subsystem", "cgroup_path"})
+
+// the scheduling policy of the first process as a label, kept apart from
+// containerMetrics as it has the policy label
+var containerSched = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "docker_container_sched_info",
+	Help: "Always 1, the scheduling policy of the first process of the container, exported with -sched.",
+}, []string{"container_id", "policy"})
+
+// the PSI series by resource and kind, some or full, kept apart from
+// containerMetrics as they have more labels
+var pressureLabels = []string{"container_id", "resource", "kind"}
+
+var (
+	pressureAvg10 = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_pressure_avg10",
+		Help: "Percentage of the last 10s tasks of the container stalled on the resource.",
+	}, pressureLabels)
+	pressureAvg60 = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_pressure_avg60",
+		Help: "Percentage of the last 60s tasks of the container stalled on the resource.",
+	}, pressureLabels)
+	pressureAvg300 = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_pressure_avg300",
+		Help: "Percentage of the last 300s tasks of the container stalled on the resource.",
+	}, pressureLabels)
+	pressureStallSeconds = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_pressure_stall_seconds_total",
+		Help: "Cumulative time tasks of the container stalled on the resource in seconds.",
+	}, pressureLabels)
+)
+
+var pressureMetrics = []containerMetric{pressureAvg10, pressureAvg60, pressureAvg300, pressureStallSeconds}
+
+// the huge page series by page size, kept apart from containerMetrics as
+// they have the pagesize label
+var hugetlbLabels = []string{"container_id", "pagesize"}
+
+var (
+	hugetlbUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_hugetlb_usage_bytes",
+		Help: "Huge pages of the page size used by the container in bytes.",
+	}, hugetlbLabels)
+	hugetlbMaxUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_hugetlb_max_usage_bytes",
+		Help: "Maximum huge pages of the page size ever used by the container in bytes.",
+	}, hugetlbLabels)
+	hugetlbFailcnt = newCounterVec(prometheus.GaugeOpts{
+		Name: "docker_hugetlb_failcnt_total",
+		Help: "Cumulative number of huge page allocations of the container which hit the limit.",
+	}, hugetlbLabels)
+)
+
+var hugetlbMetrics = []containerMetric{hugetlbUsage, hugetlbMaxUsage, hugetlbFailcnt}
+
+// the RDMA series by device, kept apart from containerMetrics as they have
+// the device label
+var rdmaLabels = []string{"container_id", "device"}
+
+var (
+	rdmaHcaHandles = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_rdma_hca_handles",
+		Help: "HCA handles of the device used by the container, from the rdma.current.",
+	}, rdmaLabels)
+	rdmaHcaObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "docker_rdma_hca_objects",
+		Help: "HCA objects of the device used by the container, from the rdma.current.",
+	}, rdmaLabels)
+)
+
+var rdmaMetrics = []containerMetric{rdmaHcaHandles, rdmaHcaObjects}
+
+// the sums over all the running containers, a cgroup shared by several
+// containers is counted once
+var (
+	totalContainers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_total_containers",
+		Help: "Number of running containers collected in the last poll.",
+	})
+	totalCpuUsage = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_total_cpu_usage",
+		Help: "CPU time used by all the containers during the last poll in nanoseconds.",
+	})
+	totalMemoryBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_total_memory_bytes",
+		Help: "Memory used by all the containers in bytes.",
+	})
+	hostOnlineCpus = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_host_online_cpus",
+		Help: "Number of online CPUs of the host.",
+	})
+	totalPids = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_total_pids",
+		Help: "Number of processes and threads in all the containers.",
+	})
+)
+
+// the sums over the short lived containers of -other-polls and -other-age,
+// which have no series of their own
+var (
+	otherContainers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_other_containers",
+		Help: "Number of short lived containers summed into the docker_other series in the last poll.",
+	})
+	otherCpuUsage = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_other_cpu_usage",
+		Help: "CPU time used by the short lived containers during the last poll in nanoseconds.",
+	})
+	otherMemoryBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_other_memory_bytes",
+		Help: "Memory used by the short lived containers in bytes.",
+	})
+	otherPids = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_other_pids",
+		Help: "Number of processes and threads in the short lived containers.",
+	})
+)
+
+// the metrics of the collector itself
+var (
+	collectorPollSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_collector_poll_duration_seconds",
+		Help: "Time the last poll took in seconds.",
+	})
+	collectorContainers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_collector_containers",
+		Help: "Number of containers collected in the last poll.",
+	})
+	collectorWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "docker_collector_workers",
+		Help: "Number of workers the containers of the last poll were collected with.",
+	})
+	collectorErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "docker_collector_errors_total",
+		Help: "Number of failed reads of a subsystem of a container.",
+	}, []string{"subsystem"})
+	collectorFileReadErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "docker_metrics_file_read_errors_total",
+		Help: "Number of failed reads of the cgroup file by its name, like cpuacct.usage.",
+	}, []string{"file"})
+	collectorSinkDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "docker_collector_sink_dropped_total",
+		Help: "Number of polls a push sink or the gRPC streams dropped as they couldn't keep up, by sink.",
+	}, []string{"sink"})
+	collectorCpuPercentOutOfRange = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "docker_collector_cpu_percent_out_of_range_total",
+		Help: "Number of CPU percentages below 0 or above 100 times the CPUs, which were clamped.",
+	})
+)
+
+// a cumulative value read from the cgroup files. It's set like a gauge as the
+// kernel keeps the count, but exposed as a counter so OpenMetrics clients and
+// rate() treat it as one.
+type counterVec struct {
+	*prometheus.GaugeVec
+	desc   *prometheus.Desc
+	labels []string
+}
+
+func newCounterVec(opts prometheus.GaugeOpts, labels []string) *counterVec {
+	return &counterVec{
+		GaugeVec: prometheus.NewGaugeVec(opts, labels),
+		desc:     prometheus.NewDesc(opts.Name, opts.Help, labels, nil),
+		labels:   labels,
+	}
+}
+
+func (this *counterVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- this.desc
+}
+
+func (this *counterVec) Collect(ch chan<- prometheus.Metric) {
+	gauges := make(chan prometheus.Metric)
+	go func() {
+		this.GaugeVec.Collect(gauges)
+		close(gauges)
+	}()
+	for m := range gauges {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			continue
+		}
+		values := make([]string, len(this.labels))
+		for _, l := range pb.Label {
+			for i, name := range this.labels {
+				if l.GetName() == name {
+					values[i] = l.GetValue()
+				}
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(this.desc, prometheus.CounterValue, pb.GetGauge().GetValue(), values...)
+	}
+}
+
+// the metrics are registered through it, with -collector-id it adds the
+// collector label to all the series
+var registerer prometheus.Registerer = registry
+
+// register the metrics once the flags are parsed, the collector label is
+// only known then
+func registerMetrics() {
+	if collectorId != "" {
+		registerer = prometheus.WrapRegistererWith(prometheus.Labels{"collector": collectorId}, registry)
+	}
+	for _, m := range containerMetrics {
+		registerer.MustRegister(m)
+	}
+	registerer.MustRegister(cpuPercpuUsage, containerImage, containerCgroup, containerSched)
+	for _, m := range pressureMetrics {
+		registerer.MustRegister(m)
+	}
+	for _, m := range hugetlbMetrics {
+		registerer.MustRegister(m)
+	}
+	for _, m := range rdmaMetrics {
+		registerer.MustRegister(m)
+	}
+	registerer.MustRegister(totalContainers, totalCpuUsage, totalMemoryBytes, totalPids, hostOnlineCpus, containerAges)
+	registerer.MustRegister(otherContainers, otherCpuUsage, otherMemoryBytes, otherPids)
+	registerer.MustRegister(collectorPollSeconds, collectorContainers, collectorWorkers, collectorErrors, collectorFileReadErrors, collectorSinkDropped, collectorCpuPercentOutOfRange)
+	registerer.MustRegister(buildInfo)
+}
+
+func metricsHandler() http.Handler {
+	// serves OpenMetrics to the clients asking for it in the Accept header
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// publish the sample, cumulative is the stats before UpdateCpu turned the CPU usage into deltas
+func (this *Container) exportMetrics(cumulative *cgroups.Stats) {
+	usage := cumulative.CpuStats.CpuUsage
+	cpuUsageTotal.WithLabelValues(this.id).Set(float64(usage.TotalUsage))
+	cpuUserTotal.WithLabelValues(this.id).Set(float64(usage.UsageInUsermode))
+	cpuSystemTotal.WithLabelValues(this.id).Set(float64(usage.UsageInKernelmode))
+	this.exportPercpu()
+	cpuCount.WithLabelValues(this.id).Set(float64(this.cpus))
+	hostOnlineCpus.Set(float64(this.hostCpus))
+	if this.quota.sampled {
+		cpuAllocatedCores.WithLabelValues(this.id).Set(this.quota.Cores)
+	}
+	// no rate series until there is a valid delta
+	if !this.WarmingUp() {
+		cpuPercent.WithLabelValues(this.id).Set(this.CpuPercent())
+		if cpuSmoothing > 0 {
+			cpuPercentSmoothed.WithLabelValues(this.id).Set(this.CpuPercentSmoothed())
+		}
+		if this.quota.sampled {
+			cpuAllocationPercent.WithLabelValues(this.id).Set(this.quota.Percent)
+		}
+		cpuThrottledPeriods.WithLabelValues(this.id).Set(float64(this.throttle.ThrottledPeriods))
+		cpuThrottledSeconds.WithLabelValues(this.id).Set(float64(this.throttle.ThrottledTime) / 1e9)
+		cpuThrottledPercent.WithLabelValues(this.id).Set(this.throttle.Percent)
+	}
+	frozen := 0.0
+	if this.Frozen() {
+		frozen = 1
+	}
+	containerFrozen.WithLabelValues(this.id).Set(frozen)
+	containerUp.WithLabelValues(this.id).Set(1)
+	if exportCgroupDepth {
+		cgroupDepthGauge.WithLabelValues(this.id).Set(float64(this.cgroupDepth))
+	}
+	containerAge.WithLabelValues(this.id).Set(this.Age().Seconds())
+	containerRestarts.WithLabelValues(this.id).Set(float64(this.restarts))
+	containerLastUpdate.WithLabelValues(this.id).Set(float64(this.sampled.UnixNano()) / 1e9)
+	memoryUsageBytes.WithLabelValues(this.id).Set(float64(this.memory.Usage))
+	memoryLimitBytes.WithLabelValues(this.id).Set(float64(this.memory.Limit))
+	if this.hasMemoryLimit {
+		memoryLimitEffectiveBytes.WithLabelValues(this.id).Set(float64(this.memoryLimit))
+	} else {
+		memoryLimitEffectiveBytes.DeleteLabelValues(this.id)
+	}
+	memoryUtilization.WithLabelValues(this.id).Set(this.memory.Utilization)
+	memoryCacheBytes.WithLabelValues(this.id).Set(float64(this.memory.Cache))
+	memoryRssBytes.WithLabelValues(this.id).Set(float64(this.memory.Rss))
+	memorySwapBytes.WithLabelValues(this.id).Set(float64(this.memory.Swap))
+	if this.oom.sampled {
+		memoryFailcnt.WithLabelValues(this.id).Set(float64(this.oom.Failcnt))
+		memoryOom.WithLabelValues(this.id).Set(float64(this.oom.Oom))
+		memoryOomKill.WithLabelValues(this.id).Set(float64(this.oom.OomKill))
+	}
+	memoryPgfault.WithLabelValues(this.id).Set(float64(cumulative.MemoryStats.Stats["pgfault"]))
+	memoryPgmajfault.WithLabelValues(this.id).Set(float64(cumulative.MemoryStats.Stats["pgmajfault"]))
+	blkioReadBytes.WithLabelValues(this.id).Set(float64(this.blkio.ReadBytes))
+	blkioWriteBytes.WithLabelValues(this.id).Set(float64(this.blkio.WriteBytes))
+	if this.io.sampled {
+		ioReadBytes.WithLabelValues(this.id).Set(float64(this.io.ReadBytes))
+		ioWriteBytes.WithLabelValues(this.id).Set(float64(this.io.WriteBytes))
+		ioReadIos.WithLabelValues(this.id).Set(float64(this.io.ReadIos))
+		ioWriteIos.WithLabelValues(this.id).Set(float64(this.io.WriteIos))
+		ioDiscardBytes.WithLabelValues(this.id).Set(float64(this.io.DiscardBytes))
+		ioDiscardIos.WithLabelValues(this.id).Set(float64(this.io.DiscardIos))
+	}
+	if this.imageDigest != "" {
+		containerImage.WithLabelValues(this.id, this.imageDigest).Set(1)
+	}
+	if cgroupPathLabel {
+		for k, p := range this.cgroupPath {
+			containerCgroup.WithLabelValues(this.id, k, p).Set(1)
+		}
+	}
+	this.exportSched()
+	this.exportPressure()
+	this.exportHugetlb()
+	this.exportRdma()
+	if this.net.sampled {
+		netRxBytes.WithLabelValues(this.id).Set(float64(this.net.RxBytes))
+		netTxBytes.WithLabelValues(this.id).Set(float64(this.net.TxBytes))
+	}
+	pidsCurrent.WithLabelValues(this.id).Set(float64(this.pids.Current))
+	pidsLimit.WithLabelValues(this.id).Set(float64(this.pids.Limit))
+	pidsUtilization.WithLabelValues(this.id).Set(this.pids.Utilization)
+	if this.hasPidsEvents {
+		pidsMaxEvents.WithLabelValues(this.id).Set(float64(this.pidsEvents))
+	}
+	if containerInfo != nil {
+		containerInfo.WithLabelValues(this.labelValues()...).Set(1)
+	}
+}
+
+// the per CPU deltas of the current sample, the cores gone since the last
+// export lose their series
+func (this *Container) exportPercpu() {
+	var usage []uint64
+	if !this.WarmingUp() {
+		usage = this.current.CpuStats.CpuUsage.PercpuUsage
+	}
+	for i, v := range usage {
+		cpuPercpuUsage.WithLabelValues(this.id, strconv.Itoa(i)).Set(float64(v))
+	}
+	for i := len(usage); i < this.percpuExported; i++ {
+		cpuPercpuUsage.DeleteLabelValues(this.id, strconv.Itoa(i))
+	}
+	this.percpuExported = len(usage)
+}
+
+// a changed policy loses the series of the old one
+func (this *Container) exportSched() {
+	if this.schedExported != "" && this.schedExported != this.schedPolicy {
+		containerSched.DeleteLabelValues(this.id, this.schedExported)
+	}
+	this.schedExported = this.schedPolicy
+	if this.schedPolicy == "" {
+		containerNice.DeleteLabelValues(this.id)
+		return
+	}
+	containerSched.WithLabelValues(this.id, this.schedPolicy).Set(1)
+	containerNice.WithLabelValues(this.id).Set(float64(this.nice))
+}
+
+func (this *Container) exportPressure() {
+	for resource, p := range this.pressure {
+		for kind, stall := range map[string]PressureStall{"some": p.Some, "full": p.Full} {
+			pressureAvg10.WithLabelValues(this.id, resource, kind).Set(stall.Avg10)
+			pressureAvg60.WithLabelValues(this.id, resource, kind).Set(stall.Avg60)
+			pressureAvg300.WithLabelValues(this.id, resource, kind).Set(stall.Avg300)
+			pressureStallSeconds.WithLabelValues(this.id, resource, kind).Set(float64(stall.Total) / 1e6)
+		}
+	}
+}
+
+// the page sizes gone since the last export lose their series
+func (this *Container) exportHugetlb() {
+	sizes := this.hugetlbSizes()
+	for _, size := range sizes {
+		s := this.hugetlb[size]
+		hugetlbUsage.WithLabelValues(this.id, size).Set(float64(s.Usage))
+		hugetlbMaxUsage.WithLabelValues(this.id, size).Set(float64(s.MaxUsage))
+		hugetlbFailcnt.WithLabelValues(this.id, size).Set(float64(s.Failcnt))
+	}
+	for _, size := range this.hugetlbExported {
+		if _, ok := this.hugetlb[size]; !ok {
+			deleteHugetlbMetrics(this.id, size)
+		}
+	}
+	this.hugetlbExported = sizes
+}
+
+func deleteHugetlbMetrics(id string, size string) {
+	for _, m := range hugetlbMetrics {
+		m.DeleteLabelValues(id, size)
+	}
+}
+
+// the devices gone since the last export lose their series
+func (this *Container) exportRdma() {
+	devices := this.rdmaDevices()
+	for _, dev := range devices {
+		rdmaHcaHandles.WithLabelValues(this.id, dev).Set(float64(this.rdma[dev].HcaHandle))
+		rdmaHcaObjects.WithLabelValues(this.id, dev).Set(float64(this.rdma[dev].HcaObject))
+	}
+	for _, dev := range this.rdmaExported {
+		if _, ok := this.rdma[dev]; !ok {
+			deleteRdmaMetrics(this.id, dev)
+		}
+	}
+	this.rdmaExported = devices
+}
+
+func deleteRdmaMetrics(id string, dev string) {
+	for _, m := range rdmaMetrics {
+		m.DeleteLabelValues(id, dev)
+	}
+}
+
+func (this *Totals) exportMetrics() {
+	totalContainers.Set(float64(this.Containers))
+	totalCpuUsage.Set(float64(this.CpuUsage))
+	totalMemoryBytes.Set(float64(this.MemoryUsage))
+	totalPids.Set(float64(this.Pids))
+}
+
+func (this *Totals) exportOther() {
+	otherContainers.Set(float64(this.Containers))
+	otherCpuUsage.Set(float64(this.CpuUsage))
+	otherMemoryBytes.Set(float64(this.MemoryUsage))
+	otherPids.Set(float64(this.Pids))
+}
+
+// remove the series of a container which is gone, so they don't go stale
+func deleteContainerMetrics(c *Container) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.deleteMetrics()
+}
+
+// the caller holds the mutex, Update does
+func (this *Container) deleteMetrics() {
+	for _, m := range containerMetrics {
+		m.DeleteLabelValues(this.id)
+	}
+	for _, resource := range pressureResources {
+		for _, kind := range []string{"some", "full"} {
+			for _, m := range pressureMetrics {
+				m.DeleteLabelValues(this.id, resource, kind)
+			}
+		}
+	}
+	for _, size := range this.hugetlbExported {
+		deleteHugetlbMetrics(this.id, size)
+	}
+	for _, dev := range this.rdmaExported {
+		deleteRdmaMetrics(this.id, dev)
+	}
+	for i := 0; i < this.percpuExported; i++ {
+		cpuPercpuUsage.DeleteLabelValues(this.id, strconv.Itoa(i))
+	}
+	if this.imageDigest != "" {
+		containerImage.DeleteLabelValues(this.id, this.imageDigest)
+	}
+	for k, p := range this.cgroupPath {
+		containerCgroup.DeleteLabelValues(this.id, k, p)
+	}
+	if this.schedExported != "" {
+		containerSched.DeleteLabelValues(this.id, this.schedExported)
+	}
+	if containerInfo != nil {
+		containerInfo.DeleteLabelValues(this.labelValues()...)
+	}
+}
@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRegisterMetricsCollectorId(t *testing.T) {
+	savedRegistry, savedRegisterer, savedId := registry, registerer, collectorId
+	defer func() { registry, registerer, collectorId = savedRegistry, savedRegisterer, savedId }()
+	registry = prometheus.NewRegistry()
+	collectorId = "host-1"
+	registerMetrics()
+
+	hostOnlineCpus.Set(2)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) == 0 {
+		t.Fatal("no metric gathered")
+	}
+	for _, f := range families {
+		for _, m := range f.Metric {
+			found := false
+			for _, l := range m.Label {
+				found = found || l.GetName() == "collector" && l.GetValue() == "host-1"
+			}
+			if !found {
+				t.Errorf("%s has no collector label: %v", f.GetName(), m.Label)
+			}
+		}
+	}
+
+	// the push sinks and the JSON carry it too
+	s := ContainerStats{Id: "a", Collector: collectorId}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, "id=a,collector=host-1 ") || !strings.Contains(statsd, "|#container_id:a,collector:host-1") {
+		t.Errorf("no collector tag in\n%s\n%s", influx, statsd)
+	}
+}
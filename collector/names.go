@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the ordered sources of the exported container name, set by -name-source
+// like label:app,docker-name,id. The first source having a value wins.
+var nameSources = []string{"docker-name", "id"}
+
+func validateNameSources(sources []string) error {
+	for _, src := range sources {
+		if src == "id" || src == "docker-name" {
+			continue
+		}
+		if strings.HasPrefix(src, "label:") && len(src) > len("label:") {
+			continue
+		}
+		return fmt.Errorf("unknown name source %q, must be label:<key>, docker-name or id", src)
+	}
+	return nil
+}
+
+func shortId(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// the running containers by id as listed by the docker API. It's only
+// refreshed when an unknown id shows up, so the socket isn't asked each poll.
+// A refresh swaps in a new map, the poll and the HTTP handlers read it under
+// the mutex.
+var dockerContainers = make(map[string]ContainerSummary)
+var dockerContainersMutex sync.RWMutex
+
+func refreshDockerContainers(ctx context.Context) {
+	list, err := dockerClient().List(ctx)
+	if err != nil {
+		log.Debugf("failed to list the containers from the docker API: %s", err.Error())
+		return
+	}
+	refreshed := make(map[string]ContainerSummary, len(list))
+	for _, c := range list {
+		refreshed[c.Id] = c
+	}
+	dockerContainersMutex.Lock()
+	dockerContainers = refreshed
+	dockerContainersMutex.Unlock()
+}
+
+func getDockerContainer(id string) (summary ContainerSummary, ok bool) {
+	dockerContainersMutex.RLock()
+	defer dockerContainersMutex.RUnlock()
+	summary, ok = dockerContainers[id]
+	return
+}
+
+func lookupDockerContainer(ctx context.Context, id string) (summary ContainerSummary, ok bool) {
+	if summary, ok = getDockerContainer(id); ok {
+		return
+	}
+	refreshDockerContainers(ctx)
+	return getDockerContainer(id)
+}
+
+// get the display name of the container from the configured sources, falls
+// back to the short id
+func resolveName(ctx context.Context, id string) string {
+	for _, src := range nameSources {
+		switch {
+		case src == "id":
+			return shortId(id)
+		case src == "docker-name":
+			if c, ok := lookupDockerContainer(ctx, id); ok && c.Name() != "" {
+				return c.Name()
+			}
+		case strings.HasPrefix(src, "label:"):
+			if c, ok := lookupDockerContainer(ctx, id); ok {
+				if v := c.Labels[strings.TrimPrefix(src, "label:")]; v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return shortId(id)
+}
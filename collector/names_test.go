@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestResolveNameConcurrent(t *testing.T) {
+	a, b := fakeId("a"), fakeId("b")
+	newFakeDocker(t,
+		ContainerSummary{Id: a, Names: []string{"/web"}, Labels: map[string]string{"app": "shop"}},
+		ContainerSummary{Id: b, Names: []string{"/db"}})
+	defer func(saved []string) { nameSources = saved }(nameSources)
+	nameSources = []string{"label:app", "docker-name", "id"}
+
+	// the poll resolves the names while the HTTP handlers do the same and an
+	// unknown id refreshes the list under them
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if got := resolveName(context.Background(), a); got != "shop" {
+				t.Errorf("name of a = %q, want shop", got)
+			}
+			if got := resolveName(context.Background(), b); got != "db" {
+				t.Errorf("name of b = %q, want db", got)
+			}
+			if got := resolveName(context.Background(), fakeId("c")); got != shortId(fakeId("c")) {
+				t.Errorf("name of c = %q, want the short id", got)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
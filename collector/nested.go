@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"os"
+	"path"
+	"sort"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// look for containers this many dirs below the subsystem mount points, like
+// the inner containers of docker-in-docker at docker/<id>/docker/<id>, set by
+// -nested-depth. 0 only looks in the cgroup parents of the docker drivers.
+var nestedDepth int
+
+// the deepest -nested-depth, a deeper tree is most likely a mount loop
+const maxNestedDepth = 16
+
+// the dirs of the containers found by the last walk relative to the mount
+// points, by container id
+var nestedPaths struct {
+	sync.Mutex
+	paths map[string]string
+}
+
+// the dir of a container found below the cgroup parents, relative to the mount point
+func nestedCgroupPath(id string) (rel string, ok bool) {
+	nestedPaths.Lock()
+	defer nestedPaths.Unlock()
+	rel, ok = nestedPaths.paths[id]
+	return
+}
+
+// walk the subsystem trees for dirs named after a container id, each mount
+// of co-mounted controllers once. The shallowest dir of an id wins.
+func findNestedContainers(cpath map[string]string) (ids []string) {
+	found := make(map[string]string)
+	for dir := range coMounted(cpath) {
+		walkNested(dir, "", 1, found)
+	}
+	nestedPaths.Lock()
+	nestedPaths.paths = found
+	nestedPaths.Unlock()
+	ids = make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return
+}
+
+// the symlinks aren't followed, ReadDir doesn't report them as dirs, so a
+// link can't loop back up the tree
+func walkNested(root string, rel string, depth int, found map[string]string) {
+	flist, err := files.ReadDir(path.Join(root, rel))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.WithFields(log.Fields{
+				"dir":   path.Join(root, rel),
+				"error": err.Error(),
+			}).Debug("failed to list the cgroup dir")
+		}
+		return
+	}
+	for _, f := range flist {
+		if !f.IsDir() {
+			continue
+		}
+		child := path.Join(rel, f.Name())
+		if id := containerIdRe.FindString(f.Name()); id != "" {
+			if p, ok := found[id]; !ok || len(child) < len(p) {
+				found[id] = child
+			}
+		}
+		if depth < nestedDepth {
+			walkNested(root, child, depth+1, found)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the network traffic of the container summed over its interfaces but lo,
+// the totals are cumulative and the rates are per second since the previous
+// sample
+type NetStats struct {
+	RxBytes     uint64
+	TxBytes     uint64
+	RxPackets   uint64
+	TxPackets   uint64
+	RxBytesRate float64
+	TxBytesRate float64
+	sampled     bool
+}
+
+// sum the counters of /proc/[pid]/net/dev, after the two header lines a line
+// per interface like "eth0: 1296 16 0 0 0 0 0 0 656 8 0 ...", the receive
+// bytes and packets first, the transmit ones from the 9th field
+func readNetDev(pid int) (stats NetStats, err error) {
+	var out []byte
+	out, err = files.ReadFile(procPath(strconv.Itoa(pid), "net", "dev"))
+	if err != nil {
+		return
+	}
+	for i, line := range strings.Split(string(out), "\n") {
+		if i < 2 || line == "" {
+			continue
+		}
+		sep := strings.Index(line, ":")
+		if sep < 0 {
+			return stats, fmt.Errorf("failed to parse net/dev line %q", line)
+		}
+		if strings.TrimSpace(line[:sep]) == "lo" {
+			continue
+		}
+		fields := strings.Fields(line[sep+1:])
+		if len(fields) < 10 {
+			return stats, fmt.Errorf("failed to parse net/dev line %q", line)
+		}
+		var v [4]uint64
+		for j, f := range []int{0, 1, 8, 9} {
+			v[j], err = strconv.ParseUint(fields[f], 10, 64)
+			if err != nil {
+				return stats, fmt.Errorf("failed to parse net/dev line %q: %w", line, err)
+			}
+		}
+		stats.RxBytes += v[0]
+		stats.RxPackets += v[1]
+		stats.TxBytes += v[2]
+		stats.TxPackets += v[3]
+	}
+	return
+}
+
+// the process shares the network namespace of the host, its net/dev is the
+// host's traffic
+func inHostNetns(pid int) bool {
+	ns, err := files.Readlink(procPath(strconv.Itoa(pid), "ns", "net"))
+	if err != nil {
+		return false
+	}
+	host, err := files.Readlink(procPath("1", "ns", "net"))
+	return err == nil && ns == host
+}
+
+// read the traffic via the container's first process, nothing for the
+// containers running with --net=host
+func (this *Container) UpdateNet() {
+	previous := this.net
+	this.net = NetStats{}
+	for _, p := range this.cgroupPath {
+		pid, err := readFirstPid(p)
+		if err != nil {
+			continue
+		}
+		if inHostNetns(pid) {
+			return
+		}
+		stats, err := readNetDev(pid)
+		if err != nil {
+			this.logger.WithFields(log.Fields{
+				"pid":   pid,
+				"error": err.Error(),
+			}).Debug("failed to read net stats")
+			return
+		}
+		stats.sampled = true
+		this.net = stats
+		break
+	}
+	if !this.net.sampled || !previous.sampled || this.previousSampled.IsZero() {
+		return
+	}
+	rates := counterRates(counters{"rx": this.net.RxBytes, "tx": this.net.TxBytes},
+		counters{"rx": previous.RxBytes, "tx": previous.TxBytes}, this.sampled.Sub(this.previousSampled))
+	this.net.RxBytesRate = rates["rx"]
+	this.net.TxBytesRate = rates["tx"]
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// take a sample on each scrape of /metrics instead of every -interval, set
+// by -on-demand. The deltas are against the sample of the previous scrape,
+// -interval only bounds how long one may take.
+var onDemand bool
+
+// the polls of the scrapes one at a time, the containers, their deltas and
+// the pause cache are only safe from one poll
+var pollMutex sync.Mutex
+
+// poll before serving the metrics, a scrape waits for the one before
+func onDemandHandler(poll func(ctx context.Context) error, metrics http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pollMutex.Lock()
+		defer pollMutex.Unlock()
+		ctx, cancel := context.WithTimeout(r.Context(), options().interval)
+		pollDone(poll(ctx))
+		cancel()
+		metrics.ServeHTTP(w, r)
+	})
+}
+
+// take the baseline of the deltas, then poll on each scrape until a signal
+func runOnDemand(poll func(ctx context.Context) error) {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	reload := make(chan os.Signal, 1)
+	if configFile != "" {
+		signal.Notify(reload, syscall.SIGHUP)
+	}
+	pollDone(pollWithTimeout(poll))
+	go serveHTTP(listenAddr, onDemandHandler(poll, metricsHandler()))
+	for {
+		select {
+		case sig := <-stop:
+			// the last scrape is done before the sinks are flushed
+			pollMutex.Lock()
+			log.Infof("got %s, exit", sig)
+			return
+		case <-reload:
+			pollMutex.Lock()
+			if err := reloadConfig(commandLine, configFile); err != nil {
+				log.Errorf("failed to reload the config, keep the previous one: %s", err.Error())
+			}
+			pollMutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestOnDemandPollsEachScrape(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("d")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	t.Cleanup(func() { deleteContainerMetrics(container) })
+
+	// each poll adds 1ms of CPU, the scrapes check no two polls overlap
+	var polls, running, overlapped int32
+	poll := func(ctx context.Context) error {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		defer atomic.AddInt32(&running, -1)
+		n := atomic.AddInt32(&polls, 1)
+		host.setCpu(id, uint64(n)*1000000, 0, 0)
+		updateContainers(ctx, []*Container{container}, 1)
+		return nil
+	}
+	// the baseline poll of runOnDemand
+	pollDone(poll(context.Background()))
+
+	server := httptest.NewServer(onDemandHandler(poll, metricsHandler()))
+	defer server.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Errorf("scrape: %s", err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("scrape returned %d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&polls); got != 9 {
+		t.Errorf("%d polls, want the baseline and one per scrape", got)
+	}
+	if atomic.LoadInt32(&overlapped) != 0 {
+		t.Error("the polls of two scrapes overlapped")
+	}
+	// the delta of the last scrape is against the sample of the one before
+	if got := testutil.ToFloat64(cpuPercent.WithLabelValues(id)); got <= 0 {
+		t.Errorf("docker_cpu_percent %f, want a delta against the previous scrape", got)
+	}
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"path"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the memory limit hits and OOM kills of the container, cumulative and
+// during the last poll
+type OomInfo struct {
+	// the times the usage hit the limit, memory.failcnt on v1 and the "max"
+	// events of memory.events on v2
+	Failcnt uint64
+	// the times the OOM killer was invoked, only on v2
+	Oom uint64
+	// the processes killed by the OOM killer, the oom_kill of memory.oom_control
+	// on v1 (since linux 4.13) and of memory.events on v2
+	OomKill uint64
+	// the increase since the previous poll
+	FailcntDelta uint64
+	OomDelta     uint64
+	OomKillDelta uint64
+	sampled      bool
+}
+
+// read the OOM counters from memory.failcnt and memory.oom_control on v1 or
+// memory.events on v2
+func readOomCounters(memoryPath string, v2 bool) (info OomInfo, err error) {
+	if v2 {
+		var events map[string]uint64
+		events, err = readKeyValues(path.Join(memoryPath, "memory.events"))
+		if err != nil {
+			return
+		}
+		info.Failcnt = events["max"]
+		info.Oom = events["oom"]
+		info.OomKill = events["oom_kill"]
+		return
+	}
+	info.Failcnt, err = readCgroupUint(path.Join(memoryPath, "memory.failcnt"))
+	if err != nil {
+		return
+	}
+	// oom_control also holds oom_kill_disable and under_oom
+	if control, cerr := readKeyValues(path.Join(memoryPath, "memory.oom_control")); cerr == nil {
+		info.OomKill = control["oom_kill"]
+	}
+	return
+}
+
+func (this *Container) UpdateOom() {
+	memoryPath, ok := this.controllerPath("memory")
+	if !ok {
+		this.oom.sampled = false
+		return
+	}
+	info, err := readOomCounters(memoryPath, isCgroupV2(this.cgroupPath))
+	if err != nil {
+		this.logger.WithFields(log.Fields{
+			"subsystem": "memory",
+			"error":     err.Error(),
+		}).Debug("failed to read the OOM counters")
+		this.oom.sampled = false
+		return
+	}
+	if noDelta {
+		info.FailcntDelta = info.Failcnt
+		info.OomDelta = info.Oom
+		info.OomKillDelta = info.OomKill
+	} else if this.oom.sampled {
+		info.FailcntDelta = counterDelta(info.Failcnt, this.oom.Failcnt)
+		info.OomDelta = counterDelta(info.Oom, this.oom.Oom)
+		info.OomKillDelta = counterDelta(info.OomKill, this.oom.OomKill)
+	}
+	info.sampled = true
+	// the kills since the previous poll, with -no-delta too where the delta
+	// fields are cumulative, else the warning repeats every poll
+	if this.oom.sampled {
+		if kills := counterDelta(info.OomKill, this.oom.OomKill); kills > 0 {
+			this.logger.WithField("oom_kills", kills).Warn("processes of the container were OOM killed")
+		}
+	}
+	this.oom = info
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+func TestUpdateOomWarnsOnce(t *testing.T) {
+	for _, cumulative := range []bool{false, true} {
+		host := newFakeHost(t)
+		id := fakeId("a")
+		host.addContainer(id)
+		container, err := NewContainer(context.Background(), id)
+		if err != nil {
+			t.Fatalf("NewContainer: %s", err)
+		}
+		var buf bytes.Buffer
+		logger := log.New()
+		logger.Out = &buf
+		container.logger = log.NewEntry(logger)
+		saved := noDelta
+		noDelta = cumulative
+
+		host.writeCgroup("memory", id, "memory.oom_control", "oom_kill_disable 0\nunder_oom 0\noom_kill 2\n")
+		container.UpdateOom()
+		host.writeCgroup("memory", id, "memory.oom_control", "oom_kill_disable 0\nunder_oom 0\noom_kill 3\n")
+		container.UpdateOom()
+		container.UpdateOom()
+		noDelta = saved
+
+		// the kills before the first sample aren't news, the one since is
+		// warned about once
+		if n := strings.Count(buf.String(), "OOM killed"); n != 1 {
+			t.Errorf("-no-delta %t: warned %d times, want once:\n%s", cumulative, n, buf.String())
+		}
+		if !strings.Contains(buf.String(), "oom_kills=1") {
+			t.Errorf("-no-delta %t: the warning doesn't count 1 kill:\n%s", cumulative, buf.String())
+		}
+	}
+}
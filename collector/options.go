@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// the options a SIGHUP can change, as read by the poll, the sinks and the
+// HTTP and gRPC handlers. A reload swaps them as a whole, so a reader sees
+// them before or after it. The flags only set the staged values Run and
+// reloadConfig build these from.
+type runtimeOptions struct {
+	interval        time.Duration
+	intervalJitter  float64
+	containerFilter []string
+	labelFilter     map[string]string
+	outputFormat    string
+	sinkQueue       int
+	// the sink of the -output and the exporter adding the -output-file to it
+	outputSink Exporter
+	exporter   Exporter
+}
+
+var liveOptions atomic.Value
+
+func init() {
+	liveOptions.Store(runtimeOptions{
+		interval:     interval,
+		labelFilter:  make(map[string]string),
+		outputFormat: outputFormat,
+		sinkQueue:    sinkQueue,
+		exporter:     humanExporter{},
+	})
+}
+
+// the options in effect, the slices and maps must not be changed
+func options() runtimeOptions {
+	return liveOptions.Load().(runtimeOptions)
+}
+
+func setOptions(o runtimeOptions) {
+	liveOptions.Store(o)
+}
@@ -0,0 +1,21 @@
+package collector
+
+import (
+	"time"
+)
+
+// containers seen in fewer polls than otherPolls or younger than otherAge are
+// summed into a single "other" line instead of one line per container, set by
+// -other-polls and -other-age. Zero disables the check.
+var otherPolls int
+var otherAge time.Duration
+
+func (this *Container) ShortLived() bool {
+	if otherPolls > 0 && this.samples < otherPolls {
+		return true
+	}
+	if otherAge > 0 && time.Since(this.firstSeen) < otherAge {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestShortLivedSummedIntoOther(t *testing.T) {
+	host := newFakeHost(t)
+	saved := otherPolls
+	otherPolls = 3
+	defer func() { otherPolls = saved }()
+	id := fakeId("a")
+	host.addContainer(id)
+	host.setMemory(id, 4096, 0)
+	container := sampledContainer(t, host, id)
+
+	// two samples, still short lived: no series of its own
+	if !container.ShortLived() {
+		t.Fatal("the container of 2 samples is not short lived")
+	}
+	for _, m := range []containerMetric{memoryUsageBytes, containerUp, containerCollectSeconds} {
+		if m.DeleteLabelValues(id) {
+			t.Errorf("a %T series of the short lived container", m)
+		}
+	}
+	var other Totals
+	other.Add(container)
+	other.exportOther()
+	if got := testutil.ToFloat64(otherMemoryBytes); got != 4096 {
+		t.Errorf("docker_other_memory_bytes = %f, want 4096", got)
+	}
+	if got := testutil.ToFloat64(otherContainers); got != 1 {
+		t.Errorf("docker_other_containers = %f, want 1", got)
+	}
+
+	// the third sample gets its own series
+	container.Update(context.Background())
+	if got := testutil.ToFloat64(memoryUsageBytes.WithLabelValues(id)); got != 4096 {
+		t.Errorf("docker_memory_usage_bytes = %f after 3 samples, want 4096", got)
+	}
+	(&Totals{}).exportOther()
+}
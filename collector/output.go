@@ -0,0 +1,316 @@
+package collector
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the output format, human, json, influx, prometheus or statsd, set by -output
+var outputFormat = "human"
+
+// with -output json print one object per line instead of an array per poll,
+// set by -stream
+var outputStream bool
+
+// the version of the JSON fields, in every object of -output json and the
+// -output-file. A field added bumps the minor version, a field removed,
+// renamed or changing its meaning or unit bumps the major version.
+//
+//	1.0  the fields up to io_read_iops
+//	1.1  io_discard_bytes_per_sec, io_discard_iops
+//	1.2  collect_seconds
+//	1.3  pids_max_events
+//	1.4  sched_policy, nice
+//	1.5  memory_pgfault, memory_pgmajfault
+//	1.6  shared
+//	1.7  cgroup_depth
+//	1.8  rdma
+//	1.9  memory_limit_effective
+//	1.10 image_digest
+//	1.11 cgroup_path
+//	1.12 collector
+//	1.13 time_unit
+const jsonSchemaVersion = "1.13"
+
+// the stats of one container as printed by -output json
+type ContainerStats struct {
+	SchemaVersion string `json:"schema_version"`
+	// the -collector-id
+	Collector string `json:"collector,omitempty"`
+	Id        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	// the repo digest of the image like nginx@sha256:..., with -image-digest
+	ImageDigest string `json:"image_digest,omitempty"`
+	Status      string `json:"status"`
+	// another container has the same cgroups, its stats are the same
+	Shared bool `json:"shared,omitempty"`
+	// the dirs of the cgroup below the subsystem mount point, with -cgroup-depth
+	CgroupDepth int `json:"cgroup_depth,omitempty"`
+	// the cgroup dir of each subsystem, with -cgroup-path-label
+	CgroupPath map[string]string `json:"cgroup_path,omitempty"`
+	// the cgroup parent the container was found under
+	CgroupParent string `json:"cgroup_parent,omitempty"`
+	// since the collector first saw the container or its restart
+	AgeSeconds float64 `json:"age_seconds"`
+	// when the last successful sample was taken, zero before the first
+	LastUpdate time.Time `json:"last_update"`
+	// the restarts seen since the collector discovered the container
+	Restarts uint64 `json:"restarts"`
+	// the time the last collection of the container took
+	CollectSeconds float64 `json:"collect_seconds"`
+	// RUNNING or FROZEN when the container is paused
+	State string `json:"state,omitempty"`
+	// false until the second sample, the delta based stats are 0 before
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+	// the subsystems which can't be read for a permission
+	Unavailable []string `json:"unavailable,omitempty"`
+	// the scheduling policy like normal or fifo and the nice value of the first
+	// process, with -sched
+	SchedPolicy string `json:"sched_policy,omitempty"`
+	Nice        *int   `json:"nice,omitempty"`
+	// the command of the first process, with -command
+	Comm    string `json:"comm,omitempty"`
+	Cmdline string `json:"cmdline,omitempty"`
+	// the exported xattr and docker labels
+	Labels map[string]string `json:"labels,omitempty"`
+	// the raw cumulative CPU time in nanoseconds, for the consumers computing
+	// their own rates
+	CpuUsageTotal  uint64 `json:"cpu_usage_total"`
+	CpuUserTotal   uint64 `json:"cpu_user_total"`
+	CpuSystemTotal uint64 `json:"cpu_system_total"`
+	// the -time-unit of cpu_usage, cpu_user, cpu_system, cpu_percpu and
+	// cpu_throttled_time
+	TimeUnit string `json:"time_unit"`
+	// the CPU time used since the previous sample in the -time-unit
+	CpuUsage   float64 `json:"cpu_usage"`
+	CpuPercent float64 `json:"cpu_percent"`
+	// the moving average of the percentage with -cpu-smoothing
+	CpuPercentSmoothed float64 `json:"cpu_percent_smoothed,omitempty"`
+	// the CPUs the percentage is relative to, the cpuset or the online host CPUs
+	Cpus     int `json:"cpus"`
+	HostCpus int `json:"host_cpus"`
+	// the user and system CPU time in the -time-unit and their share of the usage
+	CpuUser          float64 `json:"cpu_user"`
+	CpuSystem        float64 `json:"cpu_system"`
+	CpuUserPercent   float64 `json:"cpu_user_percent"`
+	CpuSystemPercent float64 `json:"cpu_system_percent"`
+	// the CPU time used on each core in the -time-unit, indexed by core
+	CpuPercpu []float64 `json:"cpu_percpu,omitempty"`
+	// the cores the CFS quota allows and the share of them used
+	CpuAllocatedCores    float64 `json:"cpu_allocated_cores,omitempty"`
+	CpuAllocationPercent float64 `json:"cpu_allocation_percent"`
+	// the CFS periods the container was throttled in since the previous sample
+	CpuThrottledPeriods uint64 `json:"cpu_throttled_periods"`
+	// the throttled time in the -time-unit
+	CpuThrottledTime    float64 `json:"cpu_throttled_time"`
+	CpuThrottledPercent float64 `json:"cpu_throttled_percent"`
+	MemoryUsage         uint64  `json:"memory_usage"`
+	MemoryLimit         uint64  `json:"memory_limit"`
+	// the smallest limit of the cgroup and its parents like a pod or slice,
+	// 0 if unlimited
+	MemoryLimitEffective uint64 `json:"memory_limit_effective,omitempty"`
+	// the usage relative to the limit, or to the host memory when unlimited
+	MemoryUtilization float64 `json:"memory_utilization"`
+	// the memory limit hits, OOM events and OOM kills since the previous sample
+	MemoryFailcnt uint64 `json:"memory_failcnt"`
+	MemoryOom     uint64 `json:"memory_oom"`
+	MemoryOomKill uint64 `json:"memory_oom_kill"`
+	// the page faults and the major ones since the previous sample
+	MemoryPgfault    uint64 `json:"memory_pgfault"`
+	MemoryPgmajfault uint64 `json:"memory_pgmajfault"`
+	Pids             uint64 `json:"pids"`
+	// 0 when there is no limit
+	PidsLimit       uint64  `json:"pids_limit"`
+	PidsUtilization float64 `json:"pids_utilization"`
+	// the forks which failed on the pids limit since the previous sample,
+	// from the v2 pids.events, absent on v1
+	PidsMaxEvents *uint64 `json:"pids_max_events,omitempty"`
+	// the block I/O bytes per second since the previous sample
+	BlkioReadRate  float64 `json:"blkio_read_bytes_per_sec"`
+	BlkioWriteRate float64 `json:"blkio_write_bytes_per_sec"`
+	// the v2 io.stat rates since the previous sample
+	IoReadRate     float64 `json:"io_read_bytes_per_sec,omitempty"`
+	IoWriteRate    float64 `json:"io_write_bytes_per_sec,omitempty"`
+	IoReadIosRate  float64 `json:"io_read_iops,omitempty"`
+	IoWriteIosRate float64 `json:"io_write_iops,omitempty"`
+	// the discards like by fstrim
+	IoDiscardRate    float64 `json:"io_discard_bytes_per_sec,omitempty"`
+	IoDiscardIosRate float64 `json:"io_discard_iops,omitempty"`
+	// the huge page usage by page size like 2MB
+	Hugetlb map[string]cgroups.HugetlbStats `json:"hugetlb,omitempty"`
+	// the PSI by resource, cpu, memory and io
+	Pressure map[string]Pressure `json:"pressure,omitempty"`
+	// the RDMA handles and objects used by device like mlx4_0
+	Rdma map[string]RdmaUsage `json:"rdma,omitempty"`
+	// the CPUs and memory nodes the container is pinned to
+	CpusetCpus []int `json:"cpuset_cpus,omitempty"`
+	CpusetMems []int `json:"cpuset_mems,omitempty"`
+	// the network bytes per second since the previous sample, 0 with --net=host
+	NetRxRate float64 `json:"net_rx_bytes_per_sec"`
+	NetTxRate float64 `json:"net_tx_bytes_per_sec"`
+	// the key=value line of the human output
+	line string
+}
+
+// a copy of the container's last sample in the output structure
+func (this *Container) Snapshot() (stats ContainerStats) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	stats.line = this.line()
+	stats.SchemaVersion = jsonSchemaVersion
+	stats.Collector = collectorId
+	stats.TimeUnit = timeUnit
+	stats.Id = this.id
+	stats.Name = this.name
+	stats.ImageDigest = this.imageDigest
+	stats.Status = this.status
+	stats.State = this.state
+	stats.Ready = this.current != nil && !this.WarmingUp()
+	stats.AgeSeconds = this.Age().Seconds()
+	stats.LastUpdate = this.sampled
+	stats.Restarts = this.restarts
+	stats.CollectSeconds = this.collectDuration.Seconds()
+	stats.CgroupParent = this.cgroupParent
+	stats.Shared = this.shared
+	if exportCgroupDepth {
+		stats.CgroupDepth = this.cgroupDepth
+	}
+	if cgroupPathLabel {
+		stats.CgroupPath = this.cgroupPath
+	}
+	stats.Labels = this.labels
+	stats.Unavailable = this.unavailable
+	if this.schedPolicy != "" {
+		stats.SchedPolicy = this.schedPolicy
+		nice := this.nice
+		stats.Nice = &nice
+	}
+	stats.Comm = this.command.Comm
+	stats.Cmdline = this.command.Cmdline
+	if this.err != nil {
+		stats.Error = this.err.Error()
+	}
+	if this.current == nil {
+		return
+	}
+	if this.previous != nil {
+		usage := this.previous.CpuStats.CpuUsage
+		stats.CpuUsageTotal = usage.TotalUsage
+		stats.CpuUserTotal = usage.UsageInUsermode
+		stats.CpuSystemTotal = usage.UsageInKernelmode
+	}
+	// the CPU usage is delta based, leave it 0 until we have a baseline
+	if !this.WarmingUp() {
+		stats.CpuUsage = scaleTime(float64(this.current.CpuStats.CpuUsage.TotalUsage))
+		stats.CpuPercent = this.CpuPercent()
+		stats.CpuPercentSmoothed = this.CpuPercentSmoothed()
+		stats.Cpus = this.cpus
+		stats.HostCpus = this.hostCpus
+		user, system, userPercent, systemPercent := this.CpuBreakdown()
+		stats.CpuUser = scaleTime(float64(user))
+		stats.CpuSystem = scaleTime(float64(system))
+		stats.CpuUserPercent = userPercent
+		stats.CpuSystemPercent = systemPercent
+		for _, v := range this.current.CpuStats.CpuUsage.PercpuUsage {
+			stats.CpuPercpu = append(stats.CpuPercpu, scaleTime(float64(v)))
+		}
+		stats.CpuAllocatedCores = this.quota.Cores
+		stats.CpuAllocationPercent = this.quota.Percent
+		stats.CpuThrottledPeriods = this.throttle.ThrottledPeriods
+		stats.CpuThrottledTime = scaleTime(float64(this.throttle.ThrottledTime))
+		stats.CpuThrottledPercent = this.throttle.Percent
+		stats.BlkioReadRate = this.blkio.ReadBytesRate
+		stats.BlkioWriteRate = this.blkio.WriteBytesRate
+		stats.IoReadRate = this.io.ReadBytesRate
+		stats.IoWriteRate = this.io.WriteBytesRate
+		stats.IoReadIosRate = this.io.ReadIosRate
+		stats.IoWriteIosRate = this.io.WriteIosRate
+		stats.IoDiscardRate = this.io.DiscardBytesRate
+		stats.IoDiscardIosRate = this.io.DiscardIosRate
+		stats.NetRxRate = this.net.RxBytesRate
+		stats.NetTxRate = this.net.TxBytesRate
+		stats.MemoryFailcnt = this.oom.FailcntDelta
+		stats.MemoryOom = this.oom.OomDelta
+		stats.MemoryOomKill = this.oom.OomKillDelta
+		stats.MemoryPgfault = this.pgfault
+		stats.MemoryPgmajfault = this.pgmajfault
+		if this.hasPidsEvents {
+			events := this.pidsEventsDelta
+			stats.PidsMaxEvents = &events
+		}
+	}
+	stats.Pressure = this.pressure
+	stats.Hugetlb = this.hugetlb
+	stats.Rdma = this.rdma
+	stats.CpusetCpus = this.cpuset.Cpus
+	stats.CpusetMems = this.cpuset.Mems
+	stats.MemoryUsage = this.memory.Usage
+	stats.MemoryLimit = this.memory.Limit
+	if this.hasMemoryLimit {
+		stats.MemoryLimitEffective = this.memoryLimit
+	}
+	stats.MemoryUtilization = this.memory.Utilization
+	stats.Pids = this.pids.Current
+	stats.PidsLimit = this.pids.Limit
+	stats.PidsUtilization = this.pids.Utilization
+	return
+}
+
+// the subsystems of the cgroup paths in a stable order
+func (this ContainerStats) cgroupSubsystems() []string {
+	subsystems := make([]string, 0, len(this.CgroupPath))
+	for k := range this.CgroupPath {
+		subsystems = append(subsystems, k)
+	}
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// the RDMA devices in a stable order
+func (this ContainerStats) rdmaDevices() []string {
+	devices := make([]string, 0, len(this.Rdma))
+	for dev := range this.Rdma {
+		devices = append(devices, dev)
+	}
+	sort.Strings(devices)
+	return devices
+}
+
+// the summed short lived containers as one entry
+func (this *Totals) Snapshot(name string) ContainerStats {
+	return ContainerStats{
+		SchemaVersion: jsonSchemaVersion,
+		Collector:     collectorId,
+		Id:            name,
+		Status:        StatusOk,
+		Ready:         true,
+		TimeUnit:      timeUnit,
+		CpuUsage:      scaleTime(float64(this.CpuUsage)),
+		MemoryUsage:   this.MemoryUsage,
+		Pids:          this.Pids,
+		line:          this.line(name),
+	}
+}
+
+// the stats of the containers and the summed "other" ones if any
+func snapshotStats(list []*Container, other *Totals) []ContainerStats {
+	stats := make([]ContainerStats, 0, len(list)+1)
+	for _, c := range list {
+		stats = append(stats, c.Snapshot())
+	}
+	if other != nil && other.Containers > 0 {
+		stats = append(stats, other.Snapshot("other"))
+	}
+	return stats
+}
+
+// hand the stats of a poll to the exporter selected by -output
+func printStats(stats []ContainerStats) {
+	if err := options().exporter.Export(stats); err != nil {
+		log.Warnf("failed to export the stats: %s", err.Error())
+	}
+}
@@ -0,0 +1,296 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// a container of the fake host sampled once per content written by set,
+// sampled twice when set is nil so it's Ready. Its series are removed when
+// the test ends.
+func sampledContainer(t *testing.T, host *fakeHost, id string, set ...func()) *Container {
+	t.Helper()
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	t.Cleanup(func() { deleteContainerMetrics(container) })
+	if len(set) == 0 {
+		set = []func(){func() {}, func() {}}
+	}
+	for i, f := range set {
+		host.setCpu(id, uint64(1000*(i+1)), 0, 0)
+		f()
+		if err := container.Update(context.Background()); err != nil {
+			t.Fatalf("Update %d: %s", i, err)
+		}
+	}
+	return container
+}
+
+// the stats of the container in each push sink
+func sinkLines(s ContainerStats) (influx string, statsd string) {
+	return string(influxLines([]ContainerStats{s}, time.Unix(1, 0), nil)), strings.Join(statsdLines([]ContainerStats{s}, time.Unix(1, 0), nil), "\n")
+}
+
+func TestSnapshotPidsMaxEvents(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	events := func(max int) func() {
+		return func() { host.writeCgroup("pids", id, "pids.events", fmt.Sprintf("max %d\n", max)) }
+	}
+	container := sampledContainer(t, host, id, events(2), events(5))
+
+	s := container.Snapshot()
+	if s.PidsMaxEvents == nil || *s.PidsMaxEvents != 3 {
+		t.Fatalf("pids_max_events = %v, want the delta 3", s.PidsMaxEvents)
+	}
+	if got := testutil.ToFloat64(pidsMaxEvents.WithLabelValues(id)); got != 5 {
+		t.Errorf("docker_pids_max_events_total = %f, want the cumulative 5", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",max_events=3i ") {
+		t.Errorf("no max_events field in\n%s", influx)
+	}
+	if !strings.Contains(statsd, "docker.pids.max_events:3|g") {
+		t.Errorf("no max_events gauge in\n%s", statsd)
+	}
+
+	// v1 has no pids.events
+	other := fakeId("b")
+	host.addContainer(other)
+	if s := sampledContainer(t, host, other).Snapshot(); s.PidsMaxEvents != nil {
+		t.Errorf("pids_max_events = %d without pids.events", *s.PidsMaxEvents)
+	}
+}
+
+func TestSnapshotSched(t *testing.T) {
+	host := newFakeHost(t)
+	saved := schedInfo
+	schedInfo = true
+	defer func() { schedInfo = saved }()
+	id := fakeId("a")
+	host.addContainer(id)
+	for name := range fakeSubsystems {
+		host.writeCgroup(name, id, "cgroup.procs", "42\n")
+	}
+	// the fields after the comm, nice (19) is 5 and policy (41) is 3, batch
+	fields := make([]string, 50)
+	for i := range fields {
+		fields[i] = "0"
+	}
+	fields[19-3], fields[41-3] = "5", "3"
+	host.write(path.Join(host.procRoot, "42", "stat"), "42 (my app) "+strings.Join(fields, " ")+"\n")
+	container := sampledContainer(t, host, id)
+
+	s := container.Snapshot()
+	if s.SchedPolicy != "batch" || s.Nice == nil || *s.Nice != 5 {
+		t.Fatalf("sched = %q %v, want batch 5", s.SchedPolicy, s.Nice)
+	}
+	if got := testutil.ToFloat64(containerSched.WithLabelValues(id, "batch")); got != 1 {
+		t.Errorf("docker_container_sched_info = %f, want 1", got)
+	}
+	if got := testutil.ToFloat64(containerNice.WithLabelValues(id)); got != 5 {
+		t.Errorf("docker_container_nice = %f, want 5", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",policy=batch nice=5i ") {
+		t.Errorf("no docker_sched line in\n%s", influx)
+	}
+	if !strings.Contains(statsd, ",sched_policy:batch") || !strings.Contains(statsd, "docker.sched.nice:5|g|#container_id:"+id) {
+		t.Errorf("no nice gauge in\n%s", statsd)
+	}
+
+	// the policy series follows a change
+	fields[41-3] = "0"
+	host.write(path.Join(host.procRoot, "42", "stat"), "42 (my app) "+strings.Join(fields, " ")+"\n")
+	container.Update(context.Background())
+	if n := testutil.CollectAndCount(containerSched); n != 1 {
+		t.Errorf("%d sched series after the policy changed, want 1", n)
+	}
+	deleteContainerMetrics(container)
+	if n := testutil.CollectAndCount(containerSched); n != 0 {
+		t.Errorf("%d sched series of the gone container", n)
+	}
+}
+
+func TestSnapshotPageFaults(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	faults := func(minor, major int) func() {
+		return func() {
+			host.writeCgroup("memory", id, "memory.stat", fmt.Sprintf("cache 0\nrss 0\npgfault %d\npgmajfault %d\n", minor, major))
+		}
+	}
+	container := sampledContainer(t, host, id, faults(100, 10), faults(150, 13))
+
+	s := container.Snapshot()
+	if s.MemoryPgfault != 50 || s.MemoryPgmajfault != 3 {
+		t.Fatalf("page faults = %d %d, want the deltas 50 3", s.MemoryPgfault, s.MemoryPgmajfault)
+	}
+	if got := testutil.ToFloat64(memoryPgmajfault.WithLabelValues(id)); got != 13 {
+		t.Errorf("docker_memory_pgmajfault_total = %f, want the cumulative 13", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",pgfault=50i,pgmajfault=3i ") {
+		t.Errorf("no page fault fields in\n%s", influx)
+	}
+	if !strings.Contains(statsd, "docker.memory.pgmajfault:3|g") {
+		t.Errorf("no page fault gauge in\n%s", statsd)
+	}
+}
+
+func TestSnapshotCgroupDepth(t *testing.T) {
+	host := newFakeHost(t)
+	saved := exportCgroupDepth
+	exportCgroupDepth = true
+	defer func() { exportCgroupDepth = saved }()
+	id := fakeId("a")
+	host.addContainer(id)
+	container := sampledContainer(t, host, id)
+
+	// docker/<id>
+	s := container.Snapshot()
+	if s.CgroupDepth != 2 {
+		t.Fatalf("cgroup_depth = %d, want 2", s.CgroupDepth)
+	}
+	if got := testutil.ToFloat64(cgroupDepthGauge.WithLabelValues(id)); got != 2 {
+		t.Errorf("docker_cgroup_depth = %f, want 2", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, " depth=2i ") {
+		t.Errorf("no docker_cgroup line in\n%s", influx)
+	}
+	if !strings.Contains(statsd, "docker.cgroup.depth:2|g") {
+		t.Errorf("no depth gauge in\n%s", statsd)
+	}
+}
+
+func TestSnapshotRdma(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container := sampledContainer(t, host, id)
+	// the rdma controller is mounted
+	dir := path.Join(host.cgroupRoot, "rdma", "docker", id)
+	host.write(path.Join(dir, "rdma.current"), "mlx4_0 hca_handle=2 hca_object=2000\nmlx4_1 hca_handle=1 hca_object=5\n")
+	container.cgroupPath["rdma"] = dir
+	if err := container.Update(context.Background()); err != nil {
+		t.Fatalf("Update: %s", err)
+	}
+
+	s := container.Snapshot()
+	if got := s.Rdma["mlx4_0"]; got.HcaHandle != 2 || got.HcaObject != 2000 {
+		t.Fatalf("rdma = %v, want mlx4_0 2 2000", s.Rdma)
+	}
+	if got := testutil.ToFloat64(rdmaHcaObjects.WithLabelValues(id, "mlx4_0")); got != 2000 {
+		t.Errorf("docker_rdma_hca_objects = %f, want 2000", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",device=mlx4_0 hca_handle=2i,hca_object=2000i ") {
+		t.Errorf("no docker_rdma line in\n%s", influx)
+	}
+	if !strings.Contains(statsd, ",device:mlx4_1") {
+		t.Errorf("no rdma gauge of mlx4_1 in\n%s", statsd)
+	}
+
+	// the device gone loses its series
+	host.write(path.Join(dir, "rdma.current"), "mlx4_0 hca_handle=2 hca_object=2000\n")
+	container.Update(context.Background())
+	if n := testutil.CollectAndCount(rdmaHcaHandles); n != 1 {
+		t.Errorf("%d rdma series after mlx4_1 is gone, want 1", n)
+	}
+}
+
+func TestSnapshotMemoryLimitEffective(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	host.setMemory(id, 1024, 8192)
+	// the parent caps the container's own limit
+	host.write(path.Join(host.dirs["memory"], "docker", "memory.limit_in_bytes"), "4096\n")
+	container := sampledContainer(t, host, id)
+
+	s := container.Snapshot()
+	if s.MemoryLimit != 8192 || s.MemoryLimitEffective != 4096 {
+		t.Fatalf("memory limit = %d effective %d, want 8192 and 4096", s.MemoryLimit, s.MemoryLimitEffective)
+	}
+	if got := testutil.ToFloat64(memoryLimitEffectiveBytes.WithLabelValues(id)); got != 4096 {
+		t.Errorf("docker_memory_limit_effective_bytes = %f, want 4096", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",limit_effective=4096i") {
+		t.Errorf("no limit_effective field in\n%s", influx)
+	}
+	if !strings.Contains(statsd, "docker.memory.limit_effective:4096|g") {
+		t.Errorf("no limit_effective gauge in\n%s", statsd)
+	}
+}
+
+func TestSnapshotImageDigest(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container := sampledContainer(t, host, id)
+	// read from the docker API once the container is discovered
+	digest := "nginx@sha256:" + fakeId("f")
+	container.imageDigest = digest
+	container.Update(context.Background())
+
+	s := container.Snapshot()
+	if s.ImageDigest != digest {
+		t.Fatalf("image_digest = %q, want %q", s.ImageDigest, digest)
+	}
+	if got := testutil.ToFloat64(containerImage.WithLabelValues(id, digest)); got != 1 {
+		t.Errorf("docker_container_image_info = %f, want 1", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",image_digest="+digest) {
+		t.Errorf("no image_digest tag in\n%s", influx)
+	}
+	if !strings.Contains(statsd, ",image_digest:"+digest) {
+		t.Errorf("no image_digest tag in\n%s", statsd)
+	}
+	deleteContainerMetrics(container)
+	if n := testutil.CollectAndCount(containerImage); n != 0 {
+		t.Errorf("%d image series of the gone container", n)
+	}
+}
+
+func TestSnapshotCgroupPath(t *testing.T) {
+	host := newFakeHost(t)
+	saved := cgroupPathLabel
+	cgroupPathLabel = true
+	defer func() { cgroupPathLabel = saved }()
+	id := fakeId("a")
+	host.addContainer(id)
+	container := sampledContainer(t, host, id)
+
+	memory := host.containerDir("memory", id)
+	s := container.Snapshot()
+	if s.CgroupPath["memory"] != memory {
+		t.Fatalf("cgroup_path = %v, want memory %s", s.CgroupPath, memory)
+	}
+	if got := testutil.ToFloat64(containerCgroup.WithLabelValues(id, "memory", memory)); got != 1 {
+		t.Errorf("docker_container_cgroup_info = %f, want 1", got)
+	}
+	influx, statsd := sinkLines(s)
+	if !strings.Contains(influx, ",cgroup_path_memory="+memory) {
+		t.Errorf("no cgroup_path_memory tag in\n%s", influx)
+	}
+	if !strings.Contains(statsd, ",cgroup_path_memory:"+memory) {
+		t.Errorf("no cgroup_path_memory tag in\n%s", statsd)
+	}
+	deleteContainerMetrics(container)
+	if n := testutil.CollectAndCount(containerCgroup); n != 0 {
+		t.Errorf("%d cgroup series of the gone container", n)
+	}
+}
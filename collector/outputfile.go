@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+)
+
+// the file to append NDJSON to each poll besides the -output, set by
+// -output-file. Empty means none.
+var outputFile string
+
+// rotate the output file once it grows beyond this many bytes, 0 never,
+// set by -output-file-max-size
+var outputFileMaxSize int64 = 100 << 20
+
+// the number of rotated files kept as <file>.1 to <file>.N, set by -output-file-keep
+var outputFileKeep = 5
+
+// appends NDJSON to a file and rotates it by size
+type fileExporter struct {
+	path    string
+	maxSize int64
+	keep    int
+	file    *os.File
+}
+
+func newFileExporter(path string, maxSize int64, keep int) (this *fileExporter, err error) {
+	this = &fileExporter{path: path, maxSize: maxSize, keep: keep}
+	if err = this.open(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (this *fileExporter) open() (err error) {
+	this.file, err = os.OpenFile(this.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open the output file %s: %w", this.path, err)
+	}
+	return
+}
+
+// <file>.N-1 becomes <file>.N and so on, the oldest is dropped
+func (this *fileExporter) rotate() (err error) {
+	this.file.Close()
+	if this.keep < 1 {
+		os.Remove(this.path)
+		return this.open()
+	}
+	os.Remove(fmt.Sprintf("%s.%d", this.path, this.keep))
+	for i := this.keep - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", this.path, i), fmt.Sprintf("%s.%d", this.path, i+1))
+	}
+	if err = os.Rename(this.path, this.path+".1"); err != nil && !os.IsNotExist(err) {
+		// keep appending to the file as it is, the next poll tries again
+		if oerr := this.open(); oerr != nil {
+			return oerr
+		}
+		return fmt.Errorf("failed to rotate the output file %s: %w", this.path, err)
+	}
+	return this.open()
+}
+
+func (this *fileExporter) Export(stats []ContainerStats) (err error) {
+	if this.maxSize > 0 {
+		if info, serr := this.file.Stat(); serr == nil && info.Size() >= this.maxSize {
+			if err = this.rotate(); err != nil {
+				return
+			}
+		}
+	}
+	if err = writeNDJSON(this.file, stats); err != nil {
+		return
+	}
+	// the sample is on disk even if the collector crashes right after
+	return this.file.Sync()
+}
+
+func (this *fileExporter) Flush() error {
+	return this.file.Sync()
+}
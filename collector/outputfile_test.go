@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestFileExporterRotate(t *testing.T) {
+	file := path.Join(t.TempDir(), "stats.json")
+	exporter, err := newFileExporter(file, 1, 2)
+	if err != nil {
+		t.Fatalf("newFileExporter: %s", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := exporter.Export([]ContainerStats{{Id: id}}); err != nil {
+			t.Fatalf("Export %s: %s", id, err)
+		}
+	}
+	for name, want := range map[string]string{file: `"id":"c"`, file + ".1": `"id":"b"`, file + ".2": `"id":"a"`} {
+		out, err := ioutil.ReadFile(name)
+		if err != nil || !strings.Contains(string(out), want) {
+			t.Errorf("%s = %q, %v, want %s", name, out, err, want)
+		}
+	}
+}
+
+func TestFileExporterRotateFails(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "stats.json")
+	exporter, err := newFileExporter(file, 1, 1)
+	if err != nil {
+		t.Fatalf("newFileExporter: %s", err)
+	}
+	if err := exporter.Export([]ContainerStats{{Id: "a"}}); err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+	// a dir at <file>.1 can't be renamed over
+	if err := os.MkdirAll(path.Join(dir, "stats.json.1", "busy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.Export([]ContainerStats{{Id: "b"}}); err == nil {
+		t.Fatal("Export gave no error when the rotation failed")
+	}
+	// the file is open again, the next poll is appended
+	if err := exporter.Export([]ContainerStats{{Id: "c"}}); err == nil {
+		t.Fatal("Export gave no error when the rotation failed again")
+	}
+	os.RemoveAll(path.Join(dir, "stats.json.1"))
+	if err := exporter.Export([]ContainerStats{{Id: "d"}}); err != nil {
+		t.Fatalf("Export after the rotation works again: %s", err)
+	}
+	out, err := ioutil.ReadFile(file + ".1")
+	if err != nil || !strings.Contains(string(out), `"id":"a"`) {
+		t.Errorf("the rotated file = %q, %v, want the first sample", out, err)
+	}
+	if out, err := ioutil.ReadFile(file); err != nil || !strings.Contains(string(out), `"id":"d"`) {
+		t.Errorf("the output file = %q, %v, want the last sample", out, err)
+	}
+}
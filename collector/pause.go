@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// collect the pause/infra sandbox containers too, set by -include-pause.
+// They are skipped by default since their stats are noise.
+var includePause bool
+
+// the pause detection per container id, the docker API is asked once per id
+var pauseCache = make(map[string]bool)
+
+// the docker API failure is only warned about once
+var pauseWarned bool
+
+// the sandbox of a kubernetes pod, by the image name or the label dockershim sets
+func isPauseImage(info *ContainerInspect) bool {
+	if info.Config.Labels["io.kubernetes.docker.type"] == "podsandbox" {
+		return true
+	}
+	image := info.Config.Image
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image == "pause" || strings.HasSuffix(image, "/pause")
+}
+
+// the container is a pause container. Without the docker API it can't be
+// told, so it's collected and not asked again.
+func isPauseContainer(ctx context.Context, id string) bool {
+	if pause, ok := pauseCache[id]; ok {
+		return pause
+	}
+	info, err := dockerClient().Inspect(ctx, id)
+	if err != nil {
+		if !pauseWarned {
+			log.Warnf("failed to inspect %s, the pause containers aren't skipped without the docker API: %s", id, err.Error())
+			pauseWarned = true
+		} else {
+			log.Debugf("failed to inspect %s for pause detection, collect it: %s", id, err.Error())
+		}
+		pauseCache[id] = false
+		return false
+	}
+	pauseCache[id] = isPauseImage(info)
+	return pauseCache[id]
+}
+
+// forget the ids which are gone
+func prunePauseCache(alive map[string]bool) {
+	for id := range pauseCache {
+		if !alive[id] {
+			delete(pauseCache, id)
+		}
+	}
+}
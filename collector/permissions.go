@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the metrics lost when a subsystem can't be read
+var subsystemMetrics = map[string]string{
+	"cpu":     "cpu throttling",
+	"cpuacct": "cpu usage",
+	"memory":  "memory usage, limit, cache, rss and swap",
+	"pids":    "pids current and limit",
+	"blkio":   "blkio read and write bytes",
+	"hugetlb": "hugetlb usage",
+}
+
+// the subsystems already warned about being unreadable
+var permissionWarned = make(map[string]bool)
+var permissionWarnedMutex sync.Mutex
+
+// fs.Manager wraps the open errors into its own messages, so the os error
+// is often only left in the text
+func isPermission(err error) bool {
+	if pe, ok := err.(*os.PathError); ok {
+		return os.IsPermission(pe)
+	}
+	return os.IsPermission(err) || strings.Contains(err.Error(), "permission denied")
+}
+
+// warn once per subsystem which metrics can't be collected without the
+// permission, e.g. when the collector doesn't run as root
+func warnPermissionDenied(subsystem string, file string) {
+	permissionWarnedMutex.Lock()
+	defer permissionWarnedMutex.Unlock()
+	if permissionWarned[subsystem] {
+		return
+	}
+	permissionWarned[subsystem] = true
+	metrics, ok := subsystemMetrics[subsystem]
+	if !ok {
+		metrics = subsystem + " stats"
+	}
+	log.WithFields(log.Fields{
+		"subsystem": subsystem,
+		"file":      file,
+	}).Warnf("permission denied, %s unavailable", metrics)
+}
+
+// the failed subsystems of a sample, the ones failed on a permission are
+// unavailable rather than broken
+type statErrors struct {
+	failed []string
+	// the names of the failed subsystems
+	subsystems []string
+	// the subsystems which can't be read for a permission
+	denied []string
+	// the subsystems with a file which didn't parse, likely read while the
+	// kernel was writing it
+	unparsed []string
+}
+
+func (this *statErrors) add(subsystem string, err error) {
+	this.failed = append(this.failed, subsystem+": "+err.Error())
+	this.subsystems = append(this.subsystems, subsystem)
+	switch {
+	case isPermission(err):
+		this.denied = append(this.denied, subsystem)
+		warnPermissionDenied(subsystem, errorFile(err))
+	case isParseError(err):
+		this.unparsed = append(this.unparsed, subsystem)
+	}
+}
+
+// fs.Manager and the v2 readers report a file they couldn't make sense of
+// as "failed to parse" or "Invalid line", strconv as a syntax error
+func isParseError(err error) bool {
+	if ne, ok := err.(*strconv.NumError); ok {
+		return ne.Err == strconv.ErrSyntax
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "failed to parse") || strings.Contains(msg, "Invalid line") ||
+		strings.Contains(msg, strconv.ErrSyntax.Error())
+}
+
+func (this *statErrors) Error() string {
+	sort.Strings(this.failed)
+	return strings.Join(this.failed, "; ")
+}
+
+// the unavailable subsystems of the sample error, sorted
+func unavailableSubsystems(err error) []string {
+	se, ok := err.(*statErrors)
+	if !ok || len(se.denied) == 0 {
+		return nil
+	}
+	denied := append([]string(nil), se.denied...)
+	sort.Strings(denied)
+	return denied
+}
@@ -0,0 +1,25 @@
+package collector
+
+import (
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the processes of the container against its pids limit
+type PidsInfo struct {
+	Current uint64
+	// 0 when there is no limit
+	Limit uint64
+	// Current / Limit, 0 when there is no limit
+	Utilization float64
+}
+
+func (this *Container) UpdatePids(stat cgroups.PidsStats) {
+	this.pids = PidsInfo{Current: stat.Current, Limit: stat.Limit}
+	// v1 reports no limit as 0, the v2 reader as the max sentinel
+	if isUnlimited(this.pids.Limit) {
+		this.pids.Limit = 0
+	}
+	if this.pids.Limit > 0 {
+		this.pids.Utilization = float64(this.pids.Current) / float64(this.pids.Limit)
+	}
+}
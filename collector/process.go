@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// collect the stats of the cgroups of these processes instead of the docker
+// containers, set by -pid as a comma separated list
+var processPids []int
+
+func parsePids(list []string) (pids []int, err error) {
+	for _, s := range list {
+		var pid int
+		pid, err = strconv.Atoi(s)
+		if err != nil || pid <= 0 {
+			return nil, fmt.Errorf("invalid pid %q", s)
+		}
+		pids = append(pids, pid)
+	}
+	return
+}
+
+// build a Container for the cgroups the process belongs to, from /proc/[pid]/cgroup
+func NewProcessContainer(pid int) (container *Container, err error) {
+	var cpath, procPath map[string]string
+	cpath, err = getCgroupsPath()
+	if err != nil {
+		return
+	}
+	procPath, err = getProcCgroupPath(pid)
+	if err != nil {
+		return
+	}
+	id := fmt.Sprintf("pid:%d", pid)
+	container = &Container{
+		id:         id,
+		cgroupPath: make(map[string]string),
+		firstSeen:  time.Now(),
+		logger:     log.WithField("container_id", id),
+	}
+	for k, mnt := range cpath {
+		if p, ok := procPath[k]; ok {
+			container.cgroupPath[k] = path.Join(mnt, p)
+		}
+	}
+	if len(container.cgroupPath) == 0 {
+		return nil, fmt.Errorf("no mounted cgroup subsystem found for pid %d", pid)
+	}
+	container.cgroupRoot = cpath
+	container.cgroupDepth = cgroupDepth(cpath, container.cgroupPath)
+	return
+}
+
+// sample the processes' cgroups with the workers of the container polls
+func getProcessStat(ctx context.Context, list []*Container) error {
+	start := time.Now()
+	_, failed := updateContainers(ctx, list, autoWorkers.workers(len(list)))
+	stats := snapshotStats(list, nil)
+	subscribers.publish(start, stats)
+	if !baselinePoll {
+		printStats(stats)
+	}
+	// a partial sample is still printed and the poll goes on
+	if failed == len(list) {
+		return fmt.Errorf("failed to get the stats of all the %d processes", failed)
+	}
+	return nil
+}
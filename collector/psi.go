@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// the resources with a PSI file <resource>.pressure in the cgroup dir
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// the stall averages in percent of the time and the total stall time in
+// microseconds of one line of a PSI file
+type PressureStall struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total"`
+}
+
+// some is the time at least one task stalled, full the time all did
+type Pressure struct {
+	Some PressureStall `json:"some"`
+	Full PressureStall `json:"full"`
+}
+
+// read a PSI file like io.pressure, it contains the lines:
+//
+// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+// full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressure(file string) (pressure Pressure, err error) {
+	var out []byte
+	out, err = files.ReadFile(file)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var stall *PressureStall
+		switch fields[0] {
+		case "some":
+			stall = &pressure.Some
+		case "full":
+			stall = &pressure.Full
+		default:
+			continue
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return pressure, fmt.Errorf("failed to parse %s entry %s", file, line)
+			}
+			if kv[0] == "total" {
+				stall.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			} else {
+				var v float64
+				v, err = strconv.ParseFloat(kv[1], 64)
+				switch kv[0] {
+				case "avg10":
+					stall.Avg10 = v
+				case "avg60":
+					stall.Avg60 = v
+				case "avg300":
+					stall.Avg300 = v
+				}
+			}
+			if err != nil {
+				return pressure, fmt.Errorf("failed to parse %s entry %s: %s", file, line, err.Error())
+			}
+		}
+	}
+	return
+}
+
+// read the PSI files of the container, the kernels without PSI or with it
+// disabled have none and the container has no pressure then
+func (this *Container) UpdatePressure() {
+	this.pressure = nil
+	for _, resource := range pressureResources {
+		dir, ok := this.controllerPath(resource)
+		if !ok {
+			continue
+		}
+		pressure, err := readPressure(path.Join(dir, resource+".pressure"))
+		if err != nil {
+			continue
+		}
+		if this.pressure == nil {
+			this.pressure = make(map[string]Pressure)
+		}
+		this.pressure[resource] = pressure
+	}
+}
+
+// the pressure of the resources as key=value pairs, sorted by resource
+func (this *Container) pressureLine() (line string) {
+	resources := make([]string, 0, len(this.pressure))
+	for r := range this.pressure {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	for _, r := range resources {
+		p := this.pressure[r]
+		line += fmt.Sprintf(" pressure.%s.some avg10=%.2f avg60=%.2f avg300=%.2f total=%d", r, p.Some.Avg10, p.Some.Avg60, p.Some.Avg300, p.Some.Total)
+		line += fmt.Sprintf(" pressure.%s.full avg10=%.2f avg60=%.2f avg300=%.2f total=%d", r, p.Full.Avg10, p.Full.Avg60, p.Full.Avg300, p.Full.Total)
+	}
+	return
+}
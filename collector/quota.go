@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the CFS quota of the container, read from the cpu controller every poll.
+// The cgroup files have no useful mtime, a changed quota doesn't touch it.
+type CpuQuotaInfo struct {
+	// the CPU time the container may use per period in microseconds, -1 unlimited
+	Quota  int64
+	Period uint64
+	// the cores the quota allows, the host cores when unlimited
+	Cores float64
+	// the share of the allocated cores used since the previous sample
+	Percent float64
+	// the quota files were read
+	sampled bool
+}
+
+// read cpu.cfs_quota_us and cpu.cfs_period_us on v1, or cpu.max holding
+// "<quota|max> <period>" on v2
+func readCpuQuota(dir string, v2 bool) (quota int64, period uint64, err error) {
+	if v2 {
+		var s string
+		s, err = readCgroupString(path.Join(dir, "cpu.max"))
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(s)
+		if len(fields) != 2 {
+			return 0, 0, fmt.Errorf("failed to parse cpu.max %q", s)
+		}
+		if period, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse cpu.max %q: %s", s, err.Error())
+		}
+		if fields[0] == "max" {
+			return -1, period, nil
+		}
+		if quota, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse cpu.max %q: %s", s, err.Error())
+		}
+		return
+	}
+	var s string
+	s, err = readCgroupString(path.Join(dir, "cpu.cfs_quota_us"))
+	if err != nil {
+		return
+	}
+	if quota, err = strconv.ParseInt(s, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse cpu.cfs_quota_us %q: %s", s, err.Error())
+	}
+	period, err = readCgroupUint(path.Join(dir, "cpu.cfs_period_us"))
+	return
+}
+
+// the allocated cores and the share of them the container used
+func (this *Container) UpdateCpuQuota() {
+	dir, ok := this.controllerPath("cpu")
+	if !ok {
+		return
+	}
+	quota, period, err := readCpuQuota(dir, isCgroupV2(this.cgroupPath))
+	if err != nil {
+		this.logger.WithFields(log.Fields{
+			"subsystem": "cpu",
+			"error":     err.Error(),
+		}).Debug("failed to read the CPU quota")
+		this.quota = CpuQuotaInfo{}
+		return
+	}
+	this.quota = CpuQuotaInfo{Quota: quota, Period: period, sampled: true}
+	this.quota.Cores = float64(this.hostCpus)
+	if this.quota.Quota > 0 && this.quota.Period > 0 {
+		this.quota.Cores = float64(this.quota.Quota) / float64(this.quota.Period)
+	}
+	// the used cores over the allocated cores
+	this.quota.Percent = this.cpuPercent * float64(this.cpus) / this.quota.Cores
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestUpdateCpuQuotaRereads(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	host.writeCgroup("cpu", id, "cpu.cfs_quota_us", "50000\n")
+	host.writeCgroup("cpu", id, "cpu.cfs_period_us", "100000\n")
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	container.UpdateCpuQuota()
+	if !container.quota.sampled || container.quota.Cores != 0.5 {
+		t.Fatalf("quota = %+v, want 0.5 cores", container.quota)
+	}
+	// kernfs keeps the mtime when the quota changes
+	file := host.containerDir("cpu", id) + "/cpu.cfs_quota_us"
+	fi, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host.writeCgroup("cpu", id, "cpu.cfs_quota_us", "200000\n")
+	if err := os.Chtimes(file, time.Now(), fi.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	container.UpdateCpuQuota()
+	if container.quota.Cores != 2 {
+		t.Errorf("cores = %f after the change, want 2", container.quota.Cores)
+	}
+	// the quota is gone with its file
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	container.UpdateCpuQuota()
+	if container.quota.sampled {
+		t.Errorf("quota = %+v without the quota file", container.quota)
+	}
+}
@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// the worker pool updates the containers while the HTTP handlers read them,
+// run with -race
+func TestUpdateContainersWhileServing(t *testing.T) {
+	host := newFakeHost(t)
+	var list []*Container
+	for _, d := range []string{"1", "2", "3", "4", "5", "6"} {
+		id := fakeId(d)
+		host.addContainer(id)
+		container, err := NewContainer(context.Background(), id)
+		if err != nil {
+			t.Fatalf("NewContainer: %s", err)
+		}
+		list = append(list, container)
+	}
+	containersMutex.Lock()
+	for _, c := range list {
+		containers[c.id] = c
+	}
+	containersMutex.Unlock()
+
+	server := httptest.NewServer(newServeMux(metricsHandler()))
+	defer server.Close()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, url := range []string{"/metrics", "/containers", "/container/" + list[0].id, "/healthz"} {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				resp, err := http.Get(server.URL + url)
+				if err != nil {
+					t.Errorf("GET %s: %s", url, err)
+					return
+				}
+				ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+			}
+		}(url)
+	}
+
+	for i := 0; i < 20; i++ {
+		for _, c := range list {
+			host.setCpu(c.id, uint64(i+1)*1000000, 0, 0)
+		}
+		if errors, _ := updateContainers(context.Background(), list, 3); errors != 0 {
+			t.Errorf("poll %d: %d containers failed", i, errors)
+		}
+		markShared(list)
+		pollDone(nil)
+	}
+	close(done)
+	wg.Wait()
+}
+
+// a reader whose Stat hangs until release is closed, like a cgroup read
+// stuck in the kernel
+type hangingReader struct {
+	osReader
+	release  chan struct{}
+	returned int32
+}
+
+func (this *hangingReader) Stat(name string) (os.FileInfo, error) {
+	<-this.release
+	defer atomic.AddInt32(&this.returned, 1)
+	return this.osReader.Stat(name)
+}
+
+// the read abandoned on the timeout goes on while the container is updated
+// and retired, run with -race
+func TestUpdateAfterATimedOutRead(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	container, err := NewContainer(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewContainer: %s", err)
+	}
+	dirs := int32(len(coMounted(container.cgroupPath)))
+	reader := &hangingReader{release: make(chan struct{})}
+	files = reader
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := container.Update(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Update gave %v, want the deadline", err)
+	}
+
+	container.mutex.Lock()
+	container.cgroupPath = map[string]string{"memory": host.containerDir("memory", id)}
+	container.hugetlbExported = append(container.hugetlbExported, "2MB")
+	container.mutex.Unlock()
+	deleteContainerMetrics(container)
+	close(reader.release)
+	// the goroutine is done with files once all its dirs were checked
+	for deadline := time.Now().Add(5 * time.Second); atomic.LoadInt32(&reader.returned) < dirs; {
+		if time.Now().After(deadline) {
+			t.Fatalf("the abandoned read checked %d of %d dirs", atomic.LoadInt32(&reader.returned), dirs)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
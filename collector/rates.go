@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"time"
+)
+
+// cumulative counters by name, like the bytes read and written
+type counters map[string]uint64
+
+// the increase of a cumulative counter, 0 if the counter was reset
+func counterDelta(current, previous uint64) uint64 {
+	if current < previous {
+		return 0
+	}
+	return current - previous
+}
+
+// the increase of each counter, 0 for a counter which was reset like on a
+// container restart, or which is new
+func counterDeltas(current, previous counters) (deltas counters) {
+	deltas = make(counters, len(current))
+	for name, v := range current {
+		if prev, ok := previous[name]; ok {
+			deltas[name] = counterDelta(v, prev)
+		}
+	}
+	return
+}
+
+// the per second increase of each counter over elapsed, nil when no time passed
+func counterRates(current, previous counters, elapsed time.Duration) (rates map[string]float64) {
+	if elapsed <= 0 {
+		return nil
+	}
+	rates = make(map[string]float64, len(current))
+	for name, delta := range counterDeltas(current, previous) {
+		rates[name] = float64(delta) / elapsed.Seconds()
+	}
+	return
+}
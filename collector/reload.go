@@ -0,0 +1,155 @@
+package collector
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the options a SIGHUP applies from the -config file, the others need a
+// restart. The tracked containers and their deltas are kept, the ones the
+// new filters leave out are retired by the next poll.
+var reloadableOptions = map[string]bool{
+	"interval":        true,
+	"interval-jitter": true,
+	"filter":          true,
+	"label-filter":    true,
+	"output":          true,
+	"stream":          true,
+	"influx-url":      true,
+	"statsd-addr":     true,
+	"sink-queue":      true,
+	"push-resync":     true,
+}
+
+// the options of the -output sink, a change replaces it
+var sinkOptions = []string{"output", "stream", "influx-url", "statsd-addr", "sink-queue", "push-resync"}
+
+// the -output-file, nil without
+var outputFileSink Exporter
+
+func withOutputFile(sink Exporter) Exporter {
+	if outputFileSink == nil {
+		return sink
+	}
+	return multiExporter{sink, outputFileSink}
+}
+
+// read the config file again and apply the changed options which can be
+// changed at runtime. Nothing is changed when an option is invalid.
+func reloadConfig(fs *flag.FlagSet, file string) (err error) {
+	values, _, err := readConfig(fs, file)
+	if err != nil {
+		return
+	}
+	// the values before the reload, to roll back
+	previous := make(map[string]string)
+	changed := make(map[string]bool)
+	for _, key := range configKeys(loadedConfig.values, values) {
+		old, value := loadedConfig.values[key], values[key]
+		if old == value {
+			continue
+		}
+		fields := log.Fields{"option": key, "old": old, "new": value}
+		if loadedConfig.commandLine[key] {
+			log.WithFields(fields).Info("config option ignored, set on the command line")
+			continue
+		}
+		if !reloadableOptions[key] {
+			log.WithFields(fields).Warn("config option changed, it needs a restart")
+			continue
+		}
+		f := fs.Lookup(key)
+		// a removed option goes back to its default
+		if _, ok := values[key]; !ok {
+			value = f.DefValue
+		}
+		previous[key] = f.Value.String()
+		if err = fs.Set(key, value); err != nil {
+			err = fmt.Errorf("%s: invalid %s %q: %s", file, key, value, err.Error())
+			break
+		}
+		changed[key] = true
+		log.WithFields(fields).Info("config option changed")
+	}
+	if err == nil {
+		err = applyReloaded(fs, changed)
+	}
+	if err != nil {
+		for key, value := range previous {
+			fs.Set(key, value)
+		}
+		return
+	}
+	loadedConfig.values = values
+	return nil
+}
+
+// the keys of both configs
+func configKeys(old, values map[string]string) (keys []string) {
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range values {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return
+}
+
+// check the staged options of the reload and switch to them
+func applyReloaded(fs *flag.FlagSet, changed map[string]bool) (err error) {
+	if interval < time.Second {
+		return fmt.Errorf("invalid -interval %s, must be at least 1s", interval)
+	}
+	if intervalJitter < 0 || intervalJitter > 50 {
+		return fmt.Errorf("invalid -interval-jitter %g, must be between 0 and 50", intervalJitter)
+	}
+	if sinkQueue < 1 {
+		return fmt.Errorf("invalid -sink-queue %d, must be at least 1", sinkQueue)
+	}
+	filters, err := parseLabelFilter(parseList(fs.Lookup("label-filter").Value.String()))
+	if err != nil {
+		return fmt.Errorf("invalid -label-filter: %s", err.Error())
+	}
+	o := options()
+	replaced := o.outputSink
+	for _, key := range sinkOptions {
+		if changed[key] {
+			if o.outputSink, err = newExporter(outputFormat); err != nil {
+				return fmt.Errorf("invalid -output: %s", err.Error())
+			}
+			o.exporter = withOutputFile(o.outputSink)
+			break
+		}
+	}
+	o.interval, o.intervalJitter = interval, intervalJitter
+	o.containerFilter = parseList(fs.Lookup("filter").Value.String())
+	o.labelFilter = filters
+	o.outputFormat, o.sinkQueue = outputFormat, sinkQueue
+	setOptions(o)
+	// the polls exporting to the replaced sink finish before it's closed
+	if o.outputSink != replaced {
+		closeSink(replaced)
+	}
+	return nil
+}
+
+// hand over what the replaced sink buffered and stop it
+func closeSink(sink Exporter) {
+	if sink == nil {
+		return
+	}
+	var err error
+	if queued, ok := sink.(*queuedExporter); ok {
+		err = queued.Close()
+	} else {
+		err = sink.Flush()
+	}
+	if err != nil {
+		log.Warnf("failed to flush the replaced sink: %s", err.Error())
+	}
+}
@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// the flags of the command line the reload needs, on a set of their own
+func reloadFlags(t *testing.T) *flag.FlagSet {
+	t.Helper()
+	savedOptions, savedInterval, savedConfig, savedOutput := options(), interval, loadedConfig, outputFormat
+	t.Cleanup(func() {
+		setOptions(savedOptions)
+		interval, loadedConfig, outputFormat = savedInterval, savedConfig, savedOutput
+	})
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.DurationVar(&interval, "interval", 3*time.Second, "")
+	fs.StringVar(&outputFormat, "output", "human", "")
+	fs.String("filter", "", "")
+	fs.String("label-filter", "", "")
+	fs.String("cgroup-root", "/sys/fs/cgroup", "")
+	fs.String("docker-socket", "", "")
+	return fs
+}
+
+func writeConfig(t *testing.T, file string, data string) {
+	t.Helper()
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	fs := reloadFlags(t)
+	file := path.Join(t.TempDir(), "docker-metrics.yaml")
+	writeConfig(t, file, "interval: 10s\nfilter: web\ncgroup-root: /a\ndocker-socket: /run/a.sock\n")
+	if err := fs.Parse([]string{"-docker-socket", "/run/cli.sock"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfig(fs, file); err != nil {
+		t.Fatalf("loadConfig: %s", err)
+	}
+	o := options()
+	o.containerFilter = parseList(fs.Lookup("filter").Value.String())
+	setOptions(o)
+
+	writeConfig(t, file, "interval: 5s\nlabel-filter: team=payments\ncgroup-root: /b\ndocker-socket: /run/b.sock\n")
+	if err := reloadConfig(fs, file); err != nil {
+		t.Fatalf("reloadConfig: %s", err)
+	}
+	if o := options(); o.interval != 5*time.Second {
+		t.Errorf("interval = %s, want the reloaded 5s", o.interval)
+	}
+	// the removed filter is back to its default, collecting all
+	if o := options(); len(o.containerFilter) != 0 {
+		t.Errorf("filter = %v, want none", o.containerFilter)
+	}
+	if o := options(); !reflect.DeepEqual(o.labelFilter, map[string]string{"team": "payments"}) {
+		t.Errorf("label filter = %v", o.labelFilter)
+	}
+	// needs a restart, and the command line wins
+	if got := fs.Lookup("cgroup-root").Value.String(); got != "/a" {
+		t.Errorf("cgroup-root = %s, want /a until a restart", got)
+	}
+	if got := fs.Lookup("docker-socket").Value.String(); got != "/run/cli.sock" {
+		t.Errorf("docker-socket = %s, want the command line one", got)
+	}
+
+	// the sink is replaced
+	o = options()
+	o.outputSink = humanExporter{}
+	setOptions(o)
+	writeConfig(t, file, "interval: 5s\nlabel-filter: team=payments\ncgroup-root: /b\noutput: json\n")
+	if err := reloadConfig(fs, file); err != nil {
+		t.Fatalf("reloadConfig: %s", err)
+	}
+	if _, ok := options().exporter.(jsonExporter); !ok {
+		t.Errorf("exporter %T, want the reloaded json", options().exporter)
+	}
+
+	// an invalid option changes nothing
+	writeConfig(t, file, "interval: 10ms\nfilter: db\nlabel-filter: team=payments\ncgroup-root: /b\n")
+	if err := reloadConfig(fs, file); err == nil {
+		t.Fatal("reloadConfig took an interval of 10ms")
+	}
+	if o := options(); o.interval != 5*time.Second || len(o.containerFilter) != 0 {
+		t.Errorf("interval %s and filter %v changed by the failed reload", o.interval, o.containerFilter)
+	}
+	if got := fs.Lookup("filter").Value.String(); got != "" {
+		t.Errorf("the filter flag is %q after the failed reload", got)
+	}
+}
+
+// the handlers read the options while a SIGHUP swaps them, run with -race
+func TestReloadWhileServing(t *testing.T) {
+	fs := reloadFlags(t)
+	file := path.Join(t.TempDir(), "docker-metrics.yaml")
+	writeConfig(t, file, "interval: 5s\n")
+	if err := loadConfig(fs, file); err != nil {
+		t.Fatalf("loadConfig: %s", err)
+	}
+	markHealthy(time.Now())
+
+	server := httptest.NewServer(newServeMux(metricsHandler()))
+	defer server.Close()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, url := range []string{"/healthz", "/container/" + fakeId("a")} {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				resp, err := http.Get(server.URL + url)
+				if err != nil {
+					t.Errorf("GET %s: %s", url, err)
+					return
+				}
+				ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+			}
+		}(url)
+	}
+	for i := 0; i < 20; i++ {
+		writeConfig(t, file, fmt.Sprintf("interval: %ds\nfilter: web%d\n", 5+i%2, i))
+		if err := reloadConfig(fs, file); err != nil {
+			t.Fatalf("reload %d: %s", i, err)
+		}
+	}
+	close(done)
+	wg.Wait()
+	if o := options(); o.interval != 6*time.Second || len(o.containerFilter) != 1 || o.containerFilter[0] != "web19" {
+		t.Errorf("interval %s and filter %v after the reloads, want the last one", o.interval, o.containerFilter)
+	}
+}
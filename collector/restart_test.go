@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestUpdateDetectsARestart(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		// restart the container of the host before the third poll
+		restart func(host *fakeHost, id string)
+	}{
+		{"counter reset", func(host *fakeHost, id string) {
+			host.setCpu(id, 500, 0, 0)
+		}},
+		{"cgroup recreated", func(host *fakeHost, id string) {
+			// the old dir is kept so the new one can't reuse its inode
+			for _, sub := range []string{"cpu", "memory", "pids"} {
+				dir := host.containerDir(sub, id)
+				if err := os.Rename(dir, dir+".old"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			host.addContainer(id)
+			host.setCpu(id, 30000, 0, 0)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			host := newFakeHost(t)
+			id := fakeId("c")
+			host.addContainer(id)
+			container, err := NewContainer(context.Background(), id)
+			if err != nil {
+				t.Fatalf("NewContainer: %s", err)
+			}
+			t.Cleanup(func() { deleteContainerMetrics(container) })
+			for i, total := range []uint64{10000, 20000} {
+				host.setCpu(id, total, 0, 0)
+				if err := container.Update(context.Background()); err != nil {
+					t.Fatalf("Update %d: %s", i, err)
+				}
+			}
+			if container.WarmingUp() || container.restarts != 0 {
+				t.Fatalf("before the restart warming up %t restarts %d, want a baseline and no restart", container.WarmingUp(), container.restarts)
+			}
+
+			test.restart(host, id)
+			if err := container.Update(context.Background()); err != nil {
+				t.Fatalf("Update after the restart: %s", err)
+			}
+			if container.restarts != 1 {
+				t.Errorf("restarts = %d, want 1", container.restarts)
+			}
+			if !container.WarmingUp() {
+				t.Error("the restarted container isn't warming up again")
+			}
+			s := container.Snapshot()
+			if s.Restarts != 1 || s.Ready {
+				t.Errorf("snapshot restarts %d ready %t, want 1 and not ready", s.Restarts, s.Ready)
+			}
+			// the deltas wait for the next sample instead of underflowing
+			if s.CpuUsage != 0 || s.CpuPercent != 0 || s.CpuUser != 0 || s.CpuSystem != 0 {
+				t.Errorf("cpu usage %g percent %g user %g system %g after the restart, want 0", s.CpuUsage, s.CpuPercent, s.CpuUser, s.CpuSystem)
+			}
+			for i, core := range s.CpuPercpu {
+				if core != 0 {
+					t.Errorf("cpu %d usage %g after the restart, want 0", i, core)
+				}
+			}
+		})
+	}
+}
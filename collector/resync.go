@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"time"
+)
+
+// with the statsd and influx-url sinks send the slow changing values, like
+// the limits, only when they change and all of them this often, set by
+// -push-resync. 0 sends them every poll.
+var pushResync time.Duration
+
+// the slow changing values a push sink last sent by series. A value is
+// marked sent when the payload is built, one lost with a failed send comes
+// again with the next resync.
+type sentValues struct {
+	values   map[string]float64
+	resynced time.Time
+	// the -push-resync when the sink was made
+	every time.Duration
+}
+
+// nil when -push-resync is off, which sends all the values
+func newSentValues() *sentValues {
+	if pushResync <= 0 {
+		return nil
+	}
+	return &sentValues{every: pushResync}
+}
+
+// start a poll, all the values are sent again when the resync is due. The
+// series of the gone containers are forgotten then.
+func (this *sentValues) poll(now time.Time) {
+	if this == nil {
+		return
+	}
+	if this.values == nil || now.Sub(this.resynced) >= this.every {
+		this.values = make(map[string]float64)
+		this.resynced = now
+	}
+}
+
+// whether the value of the series is to be sent, it's unchanged since the
+// last send otherwise
+func (this *sentValues) send(series string, value float64) bool {
+	if this == nil {
+		return true
+	}
+	if last, ok := this.values[series]; ok && last == value {
+		return false
+	}
+	this.values[series] = value
+	return true
+}
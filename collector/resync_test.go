@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPushResync(t *testing.T) {
+	saved := pushResync
+	pushResync = time.Minute
+	defer func() { pushResync = saved }()
+	influxSent, statsdSent := newSentValues(), newSentValues()
+	start := time.Unix(1000, 0)
+	poll := func(after time.Duration, limit uint64) (influx string, statsd string) {
+		stats := []ContainerStats{{Id: "a", MemoryUsage: 10, MemoryLimit: limit, PidsLimit: 100}}
+		now := start.Add(after)
+		return string(influxLines(stats, now, influxSent)), strings.Join(statsdLines(stats, now, statsdSent), "\n")
+	}
+	for _, p := range []struct {
+		after time.Duration
+		limit uint64
+		sent  bool
+	}{
+		{0, 20, true},
+		// unchanged
+		{10 * time.Second, 20, false},
+		{20 * time.Second, 30, true},
+		{30 * time.Second, 30, false},
+		// the resync sends all again
+		{61 * time.Second, 30, true},
+	} {
+		influx, statsd := poll(p.after, p.limit)
+		if got := strings.Contains(influx, ",limit="); got != p.sent {
+			t.Errorf("after %s: the influx limit sent %t, want %t:\n%s", p.after, got, p.sent, influx)
+		}
+		if got := strings.Contains(statsd, "docker.memory.limit:"); got != p.sent {
+			t.Errorf("after %s: the statsd limit sent %t, want %t:\n%s", p.after, got, p.sent, statsd)
+		}
+		// the usage always goes
+		if !strings.Contains(influx, "usage=10i") || !strings.Contains(statsd, "docker.memory.bytes:10") {
+			t.Errorf("after %s: the usage wasn't sent:\n%s\n%s", p.after, influx, statsd)
+		}
+	}
+	// off, all values every poll
+	pushResync = 0
+	if newSentValues() != nil {
+		t.Error("values tracked without -push-resync")
+	}
+}
@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// keep the samples of this long in memory for /query, set by -retention.
+// Zero disables the retention.
+var retention time.Duration
+
+// the maximum of containers kept, the least recently sampled are evicted
+// first, set by -retention-containers
+var retentionContainers = 1000
+
+// the metrics kept per sample
+var retentionMetrics = []string{"cpu", "memory", "pids"}
+
+type samplePoint struct {
+	Time  time.Time
+	Value map[string]float64
+}
+
+// an in-memory store of the recent samples per container
+type SampleStore struct {
+	mutex   sync.Mutex
+	samples map[string][]samplePoint
+}
+
+var sampleStore = &SampleStore{samples: make(map[string][]samplePoint)}
+
+// record the last sample of the container
+func (this *SampleStore) Add(c *Container) {
+	c.mutex.Lock()
+	if c.current == nil {
+		c.mutex.Unlock()
+		return
+	}
+	point := samplePoint{
+		Time: c.sampled,
+		Value: map[string]float64{
+			"memory": float64(c.current.MemoryStats.Usage.Usage),
+			"pids":   float64(c.current.PidsStats.Current),
+		},
+	}
+	// the CPU usage is a delta, it's cumulative until there is a baseline
+	if !c.WarmingUp() {
+		point.Value["cpu"] = float64(c.current.CpuStats.CpuUsage.TotalUsage)
+	}
+	id := c.id
+	c.mutex.Unlock()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.samples[id] = append(this.samples[id], point)
+	this.expire(point.Time)
+}
+
+// drop the samples older than the retention and the containers over the limit
+func (this *SampleStore) expire(now time.Time) {
+	oldest := now.Add(-retention)
+	for id, points := range this.samples {
+		i := sort.Search(len(points), func(i int) bool { return points[i].Time.After(oldest) })
+		if i == len(points) {
+			delete(this.samples, id)
+			continue
+		}
+		this.samples[id] = points[i:]
+	}
+	if len(this.samples) <= retentionContainers {
+		return
+	}
+	ids := make([]string, 0, len(this.samples))
+	for id := range this.samples {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := this.samples[ids[i]], this.samples[ids[j]]
+		return a[len(a)-1].Time.Before(b[len(b)-1].Time)
+	})
+	for _, id := range ids[:len(ids)-retentionContainers] {
+		delete(this.samples, id)
+	}
+}
+
+// the points of the metric in the range up to now, as [unix seconds, value]
+func (this *SampleStore) Query(id string, metric string, since time.Time) (points [][2]float64) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	points = make([][2]float64, 0)
+	for _, p := range this.samples[id] {
+		if p.Time.Before(since) {
+			continue
+		}
+		if v, ok := p.Value[metric]; ok {
+			points = append(points, [2]float64{float64(p.Time.UnixNano()) / 1e9, v})
+		}
+	}
+	return
+}
+
+type queryResponse struct {
+	Container string       `json:"container"`
+	Metric    string       `json:"metric"`
+	Points    [][2]float64 `json:"points"`
+}
+
+// GET /query?container=<id>&metric=cpu|memory|pids&range=10m
+func serveQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id := q.Get("container")
+	metric := q.Get("metric")
+	if id == "" {
+		http.Error(w, "missing container", http.StatusBadRequest)
+		return
+	}
+	known := false
+	for _, m := range retentionMetrics {
+		known = known || m == metric
+	}
+	if !known {
+		http.Error(w, "unknown metric, must be one of cpu, memory, pids", http.StatusBadRequest)
+		return
+	}
+	window := retention
+	if v := q.Get("range"); v != "" {
+		var err error
+		window, err = time.ParseDuration(v)
+		if err != nil {
+			http.Error(w, "invalid range: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(queryResponse{
+		Container: id,
+		Metric:    metric,
+		Points:    sampleStore.Query(id, metric, time.Now().Add(-window)),
+	})
+}
@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"time"
+)
+
+// the /proc files are generated on read and a read racing with a mount
+// change can be short, so the critical reads are tried again
+const (
+	procReadAttempts = 3
+	procReadBackoff  = 50 * time.Millisecond
+)
+
+// call f until it succeeds, sleeping backoff, then twice as long, between
+// the attempts. The error of the last attempt is returned.
+func retry(attempts int, backoff time.Duration, f func() error) (err error) {
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = f(); err == nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// the JSON fields of each schema version, a field added to ContainerStats
+// fails the test until the version is bumped and its fields are recorded
+var schemaFields = map[string]string{
+	"1.13": "age_seconds,blkio_read_bytes_per_sec,blkio_write_bytes_per_sec,cgroup_depth,cgroup_parent,cgroup_path,cmdline,collect_seconds,collector,comm," +
+		"cpu_allocated_cores,cpu_allocation_percent,cpu_percent,cpu_percent_smoothed,cpu_percpu,cpu_system,cpu_system_percent,cpu_system_total," +
+		"cpu_throttled_percent,cpu_throttled_periods,cpu_throttled_time,cpu_usage,cpu_usage_total,cpu_user,cpu_user_percent,cpu_user_total,cpus," +
+		"cpuset_cpus,cpuset_mems,error,host_cpus,hugetlb,id,image_digest,io_discard_bytes_per_sec,io_discard_iops,io_read_bytes_per_sec,io_read_iops," +
+		"io_write_bytes_per_sec,io_write_iops,labels,last_update,memory_failcnt,memory_limit,memory_limit_effective,memory_oom,memory_oom_kill," +
+		"memory_pgfault,memory_pgmajfault,memory_usage,memory_utilization,name,net_rx_bytes_per_sec,net_tx_bytes_per_sec,nice,pids,pids_limit," +
+		"pids_max_events,pids_utilization,pressure,rdma,ready,restarts,sched_policy,schema_version,shared,state,status,time_unit,unavailable",
+}
+
+// the names of the JSON fields of the struct, sorted
+func jsonFields(t reflect.Type) string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("json"); tag != "" && tag != "-" {
+			names = append(names, strings.SplitN(tag, ",", 2)[0])
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func TestSchemaVersion(t *testing.T) {
+	want, ok := schemaFields[jsonSchemaVersion]
+	if !ok {
+		t.Fatalf("the fields of schema version %s aren't recorded in schemaFields", jsonSchemaVersion)
+	}
+	if got := jsonFields(reflect.TypeOf(ContainerStats{})); got != want {
+		t.Errorf("the fields of ContainerStats changed without a jsonSchemaVersion bump past %s:\n%s\nwant\n%s", jsonSchemaVersion, got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+)
+
+// the cgroup paths of the container joined in a stable order
+func (this *Container) cgroupKey() string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	paths := make([]string, 0, len(this.cgroupPath))
+	for k, v := range this.cgroupPath {
+		paths = append(paths, k+"="+v)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+// flag the containers sharing their cgroups with another container (e.g. a
+// sidecar joined to a pod cgroup), their stats are identical. The returned set
+// has one container of each group, so totals don't count a cgroup twice.
+func markShared(list []*Container) (counted map[*Container]bool) {
+	groups := make(map[string][]*Container)
+	for _, c := range list {
+		key := c.cgroupKey()
+		groups[key] = append(groups[key], c)
+	}
+	counted = make(map[*Container]bool)
+	for _, group := range groups {
+		shared := 0.0
+		if len(group) > 1 {
+			shared = 1
+		}
+		for _, c := range group {
+			c.mutex.Lock()
+			c.shared = len(group) > 1
+			if !c.ShortLived() {
+				containerShared.WithLabelValues(c.id).Set(shared)
+			}
+			c.mutex.Unlock()
+		}
+		counted[group[0]] = true
+	}
+	return
+}
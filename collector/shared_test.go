@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMarkShared(t *testing.T) {
+	host := newFakeHost(t)
+	var list []*Container
+	for _, digit := range []string{"a", "b", "c"} {
+		host.addContainer(fakeId(digit))
+		c, err := NewContainer(context.Background(), fakeId(digit))
+		if err != nil {
+			t.Fatalf("NewContainer: %s", err)
+		}
+		list = append(list, c)
+	}
+	// b is a sidecar joined to the cgroups of a
+	list[1].cgroupPath = list[0].cgroupPath
+
+	counted := markShared(list)
+	if len(counted) != 2 || !counted[list[2]] || counted[list[0]] == counted[list[1]] {
+		t.Errorf("counted %v, want c and one of a and b", counted)
+	}
+	for i, want := range []float64{1, 1, 0} {
+		c := list[i]
+		if got := testutil.ToFloat64(containerShared.WithLabelValues(c.id)); got != want {
+			t.Errorf("docker_container_shared of %s = %f, want %f", c.id, got, want)
+		}
+		s := c.Snapshot()
+		if s.Shared != (want == 1) {
+			t.Errorf("shared of %s = %t", c.id, s.Shared)
+		}
+		influx, statsd := sinkLines(s)
+		if strings.Contains(influx, ",shared=true") != s.Shared || strings.Contains(statsd, ",shared:true") != s.Shared {
+			t.Errorf("the shared tag of %s doesn't match %t:\n%s\n%s", c.id, s.Shared, influx, statsd)
+		}
+		deleteContainerMetrics(c)
+	}
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the polls buffered for each push sink, set by -sink-queue. A sink which
+// can't keep up loses its oldest poll, the collection never waits for it.
+var sinkQueue = 10
+
+// how long Flush waits for a push sink to send its buffered polls
+var sinkFlushTimeout = 10 * time.Second
+
+// hands the stats to the sink from a goroutine of its own, so a slow or
+// down endpoint only delays its own output
+type queuedExporter struct {
+	name    string
+	sink    Exporter
+	queue   chan []ContainerStats
+	pending sync.WaitGroup
+}
+
+func newQueuedExporter(name string, sink Exporter, size int) *queuedExporter {
+	this := &queuedExporter{
+		name:  name,
+		sink:  sink,
+		queue: make(chan []ContainerStats, size),
+	}
+	go this.run()
+	return this
+}
+
+func (this *queuedExporter) run() {
+	for stats := range this.queue {
+		if err := this.sink.Export(stats); err != nil {
+			log.WithField("sink", this.name).Warnf("failed to export the stats: %s", err.Error())
+		}
+		this.pending.Done()
+	}
+}
+
+// queue the stats, dropping the oldest queued poll when the queue is full
+func (this *queuedExporter) Export(stats []ContainerStats) error {
+	this.pending.Add(1)
+	for {
+		select {
+		case this.queue <- stats:
+			return nil
+		default:
+		}
+		select {
+		case <-this.queue:
+			collectorSinkDropped.WithLabelValues(this.name).Inc()
+			this.pending.Done()
+		default:
+		}
+	}
+}
+
+// wait for the queued polls to be sent, up to sinkFlushTimeout
+func (this *queuedExporter) Flush() error {
+	done := make(chan struct{})
+	go func() {
+		this.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(sinkFlushTimeout):
+		return fmt.Errorf("failed to flush the %s sink in %s, %d polls still queued", this.name, sinkFlushTimeout, len(this.queue))
+	}
+	return this.sink.Flush()
+}
+
+// flush and stop the goroutine, Export must not be called after
+func (this *queuedExporter) Close() error {
+	err := this.Flush()
+	close(this.queue)
+	return err
+}
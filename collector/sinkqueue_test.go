@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// blocks each Export until released, the polls it got are in exported
+type slowExporter struct {
+	started  chan struct{}
+	release  chan struct{}
+	exported []string
+}
+
+func (this *slowExporter) Export(stats []ContainerStats) error {
+	this.started <- struct{}{}
+	<-this.release
+	this.exported = append(this.exported, stats[0].Id)
+	return nil
+}
+
+func (this *slowExporter) Flush() error {
+	return nil
+}
+
+func TestQueuedExporterDropsTheOldest(t *testing.T) {
+	sink := &slowExporter{started: make(chan struct{}, 10), release: make(chan struct{})}
+	queued := newQueuedExporter("test", sink, 2)
+	dropped := testutil.ToFloat64(collectorSinkDropped.WithLabelValues("test"))
+
+	poll := func(id string) {
+		if err := queued.Export([]ContainerStats{{Id: id}}); err != nil {
+			t.Fatalf("Export: %s", err)
+		}
+	}
+	// the sink is stuck on the first poll, the next two fill the queue and
+	// don't block the caller
+	poll("1")
+	<-sink.started
+	poll("2")
+	poll("3")
+	poll("4")
+	poll("5")
+	if got := testutil.ToFloat64(collectorSinkDropped.WithLabelValues("test")) - dropped; got != 2 {
+		t.Errorf("dropped %f polls, want 2", got)
+	}
+
+	close(sink.release)
+	if err := queued.Flush(); err != nil {
+		t.Fatalf("Flush: %s", err)
+	}
+	if want := []string{"1", "4", "5"}; len(sink.exported) != len(want) || sink.exported[0] != "1" || sink.exported[1] != "4" || sink.exported[2] != "5" {
+		t.Errorf("exported %v, want %v", sink.exported, want)
+	}
+}
@@ -0,0 +1,39 @@
+package collector
+
+// the weight of the newest CPU percentage in the moving average, set by
+// -cpu-smoothing, 0 disables the smoothing
+var cpuSmoothing float64
+
+// turn -cpu-smoothing into the weight of the newest sample, a value below 1
+// is the weight itself and N from 1 on averages over about N samples
+func smoothingAlpha(value float64) float64 {
+	if value < 1 {
+		return value
+	}
+	return 2 / (value + 1)
+}
+
+// the exponentially weighted moving average of the CPU percentage
+type CpuSmoothing struct {
+	Percent float64
+	// the average has its first value
+	valid bool
+}
+
+// fold the CPU percentage of the last sample into the average. A reset
+// counter starts it over as the old values belong to another process.
+func (this *Container) UpdateCpuSmoothing(reset bool) {
+	if cpuSmoothing == 0 {
+		return
+	}
+	if reset || !this.smoothed.valid {
+		this.smoothed = CpuSmoothing{Percent: this.cpuPercent, valid: true}
+		return
+	}
+	this.smoothed.Percent += cpuSmoothing * (this.cpuPercent - this.smoothed.Percent)
+}
+
+// the smoothed CPU percentage, the caller holds the mutex
+func (this *Container) CpuPercentSmoothed() float64 {
+	return this.smoothed.Percent
+}
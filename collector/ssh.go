@@ -0,0 +1,139 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the ssh subcommand reads the containers of several hosts without an agent
+// deployment: each host runs a single poll of its own docker-metrics over ssh
+// and hands back the JSON array, the stats of all the hosts are printed
+// together with the host as their collector.
+//
+//	docker-metrics ssh [-remote cmd] [-timeout 30s] host...
+
+// the ssh client, a var so the tests can fake it
+var sshCommand = "ssh"
+
+// the command run on each host, it must print a JSON array of ContainerStats
+var sshRemote = "docker-metrics -once -output json"
+
+// a host taking longer is given up on
+var sshTimeout = 30 * time.Second
+
+// the hosts polled at the same time
+var sshParallel = 16
+
+// the stats a host handed back, or why it didn't
+type hostStats struct {
+	host  string
+	stats []ContainerStats
+	err   error
+}
+
+// run the remote command on the host and decode the stats it prints
+func pollHost(ctx context.Context, host string) ([]ContainerStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, sshTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, sshCommand, "-o", "BatchMode=yes", host, sshRemote)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to run %q on %s: %w: %s", sshRemote, host, err, msg)
+		}
+		return nil, fmt.Errorf("failed to run %q on %s: %w", sshRemote, host, err)
+	}
+	stats, err := Decode(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the stats of %s: %w", host, err)
+	}
+	// an old collector or one without -collector-id, tell the hosts apart
+	for i := range stats {
+		if stats[i].Collector == "" {
+			stats[i].Collector = host
+		}
+	}
+	return stats, nil
+}
+
+// poll the hosts up to sshParallel at a time, the stats are in the order of
+// the hosts
+func fanOut(ctx context.Context, hosts []string) []hostStats {
+	results := make([]hostStats, len(hosts))
+	sem := make(chan struct{}, sshParallel)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, host string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			stats, err := pollHost(ctx, host)
+			results[i] = hostStats{host: host, stats: stats, err: err}
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// run the ssh subcommand with its args, returns the exit status: 1 when a host failed, its
+// stats are missing then but the others are still printed
+func RunSSH(args []string) int {
+	fs := flag.NewFlagSet("ssh", flag.ContinueOnError)
+	fs.StringVar(&sshCommand, "ssh", sshCommand, "the ssh client, run as <ssh> -o BatchMode=yes <host> <remote>")
+	fs.StringVar(&sshRemote, "remote", sshRemote, "the command run on each host, it must print a JSON array of the stats")
+	fs.DurationVar(&sshTimeout, "timeout", sshTimeout, "give up on a host after this long")
+	fs.IntVar(&sshParallel, "parallel", sshParallel, "the hosts polled at the same time")
+	format := fs.String("output", "json", "format of the stats of all the hosts: json|influx")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s ssh [flags] host...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if sshParallel < 1 {
+		log.Errorf("invalid -parallel %d, must be at least 1", sshParallel)
+		return 2
+	}
+	var out Exporter
+	switch *format {
+	case "json":
+		out = jsonExporter{}
+	case "influx":
+		out = influxExporter{}
+	default:
+		log.Errorf("unknown -output %q, must be json or influx", *format)
+		return 2
+	}
+
+	status := 0
+	var stats []ContainerStats
+	for _, r := range fanOut(context.Background(), fs.Args()) {
+		if r.err != nil {
+			log.WithField("host", r.host).Error(r.err.Error())
+			status = 1
+			continue
+		}
+		stats = append(stats, r.stats...)
+	}
+	if err := out.Export(stats); err != nil {
+		log.Errorf("failed to export the stats: %s", err.Error())
+		return 1
+	}
+	return status
+}
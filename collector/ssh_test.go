@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+// an ssh client answering for the host in $3 like the remote docker-metrics
+const fakeSSH = `#!/bin/sh
+case "$3" in
+a) echo '[{"schema_version":"1.1","id":"1"},{"schema_version":"1.1","id":"2","collector":"rack-1"}]'; echo '{"host_schema_version":"1.0","ages":{}}' ;;
+b) echo '{"schema_version":"1.1","id":"3"}' ;;
+*) echo "connection refused" >&2; exit 255 ;;
+esac
+`
+
+func TestFanOut(t *testing.T) {
+	script := path.Join(t.TempDir(), "ssh")
+	if err := ioutil.WriteFile(script, []byte(fakeSSH), 0755); err != nil {
+		t.Fatal(err)
+	}
+	saved := sshCommand
+	sshCommand = script
+	defer func() { sshCommand = saved }()
+
+	results := fanOut(context.Background(), []string{"a", "down", "b"})
+	if len(results) != 3 {
+		t.Fatalf("%d results, want one per host", len(results))
+	}
+	a, down, b := results[0], results[1], results[2]
+	if a.err != nil || len(a.stats) != 2 || a.stats[0].Id != "1" || a.stats[0].Collector != "a" || a.stats[1].Collector != "rack-1" {
+		t.Errorf("host a: %+v %v, want 1 with the host as collector and 2 keeping its own", a.stats, a.err)
+	}
+	if down.err == nil || !strings.Contains(down.err.Error(), "connection refused") {
+		t.Errorf("host down: %v, want the ssh error", down.err)
+	}
+	if b.err != nil || len(b.stats) != 1 || b.stats[0].Collector != "b" {
+		t.Errorf("host b: %+v %v", b.stats, b.err)
+	}
+}
@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the id isn't the 64 hex digits of a docker id
+var errInvalidContainerId = errors.New("invalid container id")
+
+// read the current cumulative stats of one container, without tracking it
+func StatContainer(id string) (*cgroups.Stats, error) {
+	if !containerIdRe.MatchString(id) || len(id) != 64 {
+		return nil, fmt.Errorf("%w %q", errInvalidContainerId, id)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), options().interval)
+	defer cancel()
+	container, err := NewContainer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stat, status, err := container.getStatsContext(ctx)
+	if status == StatusError {
+		return nil, err
+	}
+	return stat, nil
+}
+
+// serve /container/<id> with the stats of the container
+func serveContainer(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/container/")
+	if id == "" {
+		http.Error(w, "missing container id", http.StatusBadRequest)
+		return
+	}
+	stat, err := StatContainer(id)
+	if errors.Is(err, errInvalidContainerId) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stat)
+}
+
+// a container tracked by the collector as listed on /containers
+type TrackedContainer struct {
+	Id     string            `json:"id"`
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Status string            `json:"status"`
+	// zero before the first sample
+	LastSample time.Time `json:"last_sample"`
+	// the container has the baseline of the delta based stats
+	Ready bool `json:"ready"`
+	// the updates failed in a row, its series are gone after -stale-after
+	FailedUpdates int `json:"failed_updates"`
+}
+
+// serve /containers with the containers tracked right now, sorted by id
+func serveContainers(w http.ResponseWriter, r *http.Request) {
+	containersMutex.Lock()
+	list := make([]*Container, 0, len(containers))
+	for _, c := range containers {
+		list = append(list, c)
+	}
+	containersMutex.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	tracked := make([]TrackedContainer, 0, len(list))
+	for _, c := range list {
+		c.mutex.Lock()
+		tracked = append(tracked, TrackedContainer{
+			Id:            c.id,
+			Name:          c.name,
+			Labels:        c.labels,
+			Status:        c.status,
+			LastSample:    c.sampled,
+			Ready:         c.current != nil && !c.WarmingUp(),
+			FailedUpdates: c.failedUpdates,
+		})
+		c.mutex.Unlock()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracked)
+}
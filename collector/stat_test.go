@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+func TestServeContainer(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("a")
+	host.addContainer(id)
+	host.setMemory(id, 4096, 0)
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/container/" + id, http.StatusOK},
+		{"/container/", http.StatusBadRequest},
+		{"/container/abc", http.StatusBadRequest},
+		{"/container/" + fakeId("A"), http.StatusBadRequest},
+		{"/container/" + fakeId("b"), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		serveContainer(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s = %d, want %d: %s", tt.path, w.Code, tt.want, w.Body.String())
+		}
+	}
+	w := httptest.NewRecorder()
+	serveContainer(w, httptest.NewRequest(http.MethodGet, "/container/"+id, nil))
+	var stat cgroups.Stats
+	if err := json.NewDecoder(w.Body).Decode(&stat); err != nil {
+		t.Fatal(err)
+	}
+	if stat.MemoryStats.Usage.Usage != 4096 {
+		t.Errorf("memory usage = %d, want 4096", stat.MemoryStats.Usage.Usage)
+	}
+}
@@ -0,0 +1,155 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// the StatsD or DogStatsD agent the -output statsd gauges are sent to over
+// UDP, set by -statsd-addr
+var statsdAddr = "127.0.0.1:8125"
+
+// the largest datagram, small enough for the MTU of most networks
+const statsdPacketSize = 1432
+
+// the separators of the DogStatsD format can't be in a tag
+var statsdTagEscaper = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+
+// a gauge of a container, delta gauges need a Ready container
+type statsdGauge struct {
+	name  string
+	value float64
+	delta bool
+}
+
+// the gauges which only change with the configuration of the container,
+// left out unchanged with -push-resync like docker.sched.nice
+var statsdSlowGauges = map[string]bool{
+	"docker.memory.limit":           true,
+	"docker.memory.limit_effective": true,
+	"docker.cgroup.depth":           true,
+}
+
+// the gauges of each container as DogStatsD lines, tagged with the container
+// id and name. The delta and rate gauges are left out until the container is
+// Ready, a 0 would look like an idle container. The slow changing gauges are
+// left out when sent says they're unchanged.
+func statsdLines(stats []ContainerStats, now time.Time, sent *sentValues) (lines []string) {
+	sent.poll(now)
+	for _, s := range stats {
+		tags := "|#container_id:" + statsdTagEscaper.Replace(s.Id)
+		if s.Collector != "" {
+			tags += ",collector:" + statsdTagEscaper.Replace(s.Collector)
+		}
+		if s.Name != "" {
+			tags += ",container_name:" + statsdTagEscaper.Replace(s.Name)
+		}
+		if s.ImageDigest != "" {
+			tags += ",image_digest:" + statsdTagEscaper.Replace(s.ImageDigest)
+		}
+		for _, k := range s.cgroupSubsystems() {
+			tags += ",cgroup_path_" + labelNameRe.ReplaceAllString(k, "_") + ":" + statsdTagEscaper.Replace(s.CgroupPath[k])
+		}
+		if s.Shared {
+			tags += ",shared:true"
+		}
+		gauges := []statsdGauge{
+			{"docker.cpu.usage", s.CpuUsage, true},
+			{"docker.cpu.percent", s.CpuPercent, true},
+			{"docker.memory.bytes", float64(s.MemoryUsage), false},
+			{"docker.memory.limit", float64(s.MemoryLimit), false},
+			{"docker.memory.utilization", s.MemoryUtilization, false},
+			{"docker.memory.pgfault", float64(s.MemoryPgfault), true},
+			{"docker.memory.pgmajfault", float64(s.MemoryPgmajfault), true},
+			{"docker.pids", float64(s.Pids), false},
+			{"docker.blkio.read_bytes_per_sec", s.BlkioReadRate, true},
+			{"docker.blkio.write_bytes_per_sec", s.BlkioWriteRate, true},
+			{"docker.net.rx_bytes_per_sec", s.NetRxRate, true},
+			{"docker.net.tx_bytes_per_sec", s.NetTxRate, true},
+		}
+		if s.MemoryLimitEffective > 0 {
+			gauges = append(gauges, statsdGauge{"docker.memory.limit_effective", float64(s.MemoryLimitEffective), false})
+		}
+		if s.CgroupDepth > 0 {
+			gauges = append(gauges, statsdGauge{"docker.cgroup.depth", float64(s.CgroupDepth), false})
+		}
+		if s.PidsMaxEvents != nil {
+			gauges = append(gauges, statsdGauge{"docker.pids.max_events", float64(*s.PidsMaxEvents), true})
+		}
+		for _, g := range gauges {
+			if g.delta && !s.Ready {
+				continue
+			}
+			if statsdSlowGauges[g.name] && !sent.send(g.name+tags, g.value) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s:%g|g%s", g.name, g.value, tags))
+		}
+		for _, dev := range s.rdmaDevices() {
+			usage := s.Rdma[dev]
+			lines = append(lines, fmt.Sprintf("docker.rdma.hca_handle:%d|g%s,device:%s", usage.HcaHandle, tags, statsdTagEscaper.Replace(dev)))
+			lines = append(lines, fmt.Sprintf("docker.rdma.hca_object:%d|g%s,device:%s", usage.HcaObject, tags, statsdTagEscaper.Replace(dev)))
+		}
+		if s.SchedPolicy != "" {
+			sched := tags + ",sched_policy:" + statsdTagEscaper.Replace(s.SchedPolicy)
+			if sent.send("docker.sched.nice"+sched, float64(*s.Nice)) {
+				lines = append(lines, fmt.Sprintf("docker.sched.nice:%d|g%s", *s.Nice, sched))
+			}
+		}
+	}
+	return
+}
+
+// pack the lines into as few datagrams as fit, a line is never split
+func statsdPackets(lines []string) (packets [][]byte) {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if buf.Len() > 0 && buf.Len()+1+len(line) > statsdPacketSize {
+			packets = append(packets, append([]byte(nil), buf.Bytes()...))
+			buf.Reset()
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(line)
+	}
+	if buf.Len() > 0 {
+		packets = append(packets, buf.Bytes())
+	}
+	return
+}
+
+// gauges to -statsd-addr. The socket is dialed again after a failed send,
+// so an agent which is down or restarting only costs the stats of the polls
+// meanwhile.
+type statsdExporter struct {
+	addr string
+	conn net.Conn
+	// the slow changing gauges sent, nil sends them all
+	sent *sentValues
+}
+
+func (this *statsdExporter) Export(stats []ContainerStats) (err error) {
+	if this.conn == nil {
+		if this.conn, err = net.Dial("udp", this.addr); err != nil {
+			this.conn = nil
+			return fmt.Errorf("failed to connect to statsd %s: %w", this.addr, err)
+		}
+	}
+	for _, packet := range statsdPackets(statsdLines(stats, time.Now(), this.sent)) {
+		if _, err = this.conn.Write(packet); err != nil {
+			this.conn.Close()
+			this.conn = nil
+			return fmt.Errorf("failed to send to statsd %s: %w", this.addr, err)
+		}
+	}
+	return nil
+}
+
+// a datagram is sent by Export
+func (this *statsdExporter) Flush() error {
+	return nil
+}
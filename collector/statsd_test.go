@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsdLines(t *testing.T) {
+	got := statsdLines([]ContainerStats{
+		{Id: "a", Name: "web|1", Ready: true, CpuUsage: 5, CpuPercent: 2.5, MemoryUsage: 10},
+		{Id: "b", MemoryUsage: 10, Pids: 1},
+	}, time.Unix(1, 0), nil)
+	want := []string{
+		"docker.cpu.usage:5|g|#container_id:a,container_name:web_1",
+		"docker.cpu.percent:2.5|g|#container_id:a,container_name:web_1",
+		"docker.memory.bytes:10|g|#container_id:a,container_name:web_1",
+		"docker.memory.limit:0|g|#container_id:a,container_name:web_1",
+		"docker.memory.utilization:0|g|#container_id:a,container_name:web_1",
+		"docker.memory.pgfault:0|g|#container_id:a,container_name:web_1",
+		"docker.memory.pgmajfault:0|g|#container_id:a,container_name:web_1",
+		"docker.pids:0|g|#container_id:a,container_name:web_1",
+		"docker.blkio.read_bytes_per_sec:0|g|#container_id:a,container_name:web_1",
+		"docker.blkio.write_bytes_per_sec:0|g|#container_id:a,container_name:web_1",
+		"docker.net.rx_bytes_per_sec:0|g|#container_id:a,container_name:web_1",
+		"docker.net.tx_bytes_per_sec:0|g|#container_id:a,container_name:web_1",
+		// warming up, no delta or rate gauge
+		"docker.memory.bytes:10|g|#container_id:b",
+		"docker.memory.limit:0|g|#container_id:b",
+		"docker.memory.utilization:0|g|#container_id:b",
+		"docker.pids:1|g|#container_id:b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStatsdPackets(t *testing.T) {
+	line := strings.Repeat("x", 600)
+	packets := statsdPackets([]string{line, line, line})
+	if len(packets) != 2 || len(packets[0]) != 1201 || len(packets[1]) != 600 {
+		t.Errorf("got %d packets, want 2 lines and 1 line without splitting one", len(packets))
+	}
+}
+
+func TestStatsdExporter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	exporter := &statsdExporter{addr: conn.LocalAddr().String()}
+	if err := exporter.Export([]ContainerStats{{Id: "a", Pids: 2}}); err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+	buf := make([]byte, statsdPacketSize)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(buf[:n]), "docker.pids:2|g|#container_id:a") {
+		t.Errorf("got %q", buf[:n])
+	}
+}
@@ -0,0 +1,130 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package statspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// StatsClient is the client API for Stats service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StatsClient interface {
+	// a Poll after each poll of the collector until the call is canceled
+	StreamStats(ctx context.Context, in *StreamStatsRequest, opts ...grpc.CallOption) (Stats_StreamStatsClient, error)
+}
+
+type statsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStatsClient(cc grpc.ClientConnInterface) StatsClient {
+	return &statsClient{cc}
+}
+
+func (c *statsClient) StreamStats(ctx context.Context, in *StreamStatsRequest, opts ...grpc.CallOption) (Stats_StreamStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Stats_ServiceDesc.Streams[0], "/dockermetrics.v1.Stats/StreamStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &statsStreamStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Stats_StreamStatsClient interface {
+	Recv() (*Poll, error)
+	grpc.ClientStream
+}
+
+type statsStreamStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *statsStreamStatsClient) Recv() (*Poll, error) {
+	m := new(Poll)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StatsServer is the server API for Stats service.
+// All implementations must embed UnimplementedStatsServer
+// for forward compatibility
+type StatsServer interface {
+	// a Poll after each poll of the collector until the call is canceled
+	StreamStats(*StreamStatsRequest, Stats_StreamStatsServer) error
+	mustEmbedUnimplementedStatsServer()
+}
+
+// UnimplementedStatsServer must be embedded to have forward compatible implementations.
+type UnimplementedStatsServer struct {
+}
+
+func (UnimplementedStatsServer) StreamStats(*StreamStatsRequest, Stats_StreamStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStats not implemented")
+}
+func (UnimplementedStatsServer) mustEmbedUnimplementedStatsServer() {}
+
+// UnsafeStatsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StatsServer will
+// result in compilation errors.
+type UnsafeStatsServer interface {
+	mustEmbedUnimplementedStatsServer()
+}
+
+func RegisterStatsServer(s grpc.ServiceRegistrar, srv StatsServer) {
+	s.RegisterService(&Stats_ServiceDesc, srv)
+}
+
+func _Stats_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StatsServer).StreamStats(m, &statsStreamStatsServer{stream})
+}
+
+type Stats_StreamStatsServer interface {
+	Send(*Poll) error
+	grpc.ServerStream
+}
+
+type statsStreamStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *statsStreamStatsServer) Send(m *Poll) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Stats_ServiceDesc is the grpc.ServiceDesc for Stats service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Stats_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dockermetrics.v1.Stats",
+	HandlerType: (*StatsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStats",
+			Handler:       _Stats_StreamStats_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stats.proto",
+}
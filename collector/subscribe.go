@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// a listener called with the start and the stats of each poll
+type pollListener func(start time.Time, stats []ContainerStats)
+
+// the listeners of the polls
+type subscriberList struct {
+	mutex     sync.Mutex
+	next      int
+	listeners map[int]pollListener
+}
+
+var subscribers = &subscriberList{listeners: make(map[int]pollListener)}
+
+// call listener with the stats of every poll until the returned unsubscribe
+// is called, the polls of Snapshot, of the command line and the ones it
+// doesn't print like the baseline of -once or with -summary. The listeners
+// run on the poll in the order they subscribed, before the stats are
+// exported, and share the slice, so they must not change it and should
+// return quickly.
+func (this *Collector) Subscribe(listener func([]ContainerStats)) (unsubscribe func()) {
+	return subscribe(func(_ time.Time, stats []ContainerStats) { listener(stats) })
+}
+
+func subscribe(listener pollListener) (unsubscribe func()) {
+	subscribers.mutex.Lock()
+	defer subscribers.mutex.Unlock()
+	id := subscribers.next
+	subscribers.next++
+	subscribers.listeners[id] = listener
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			subscribers.mutex.Lock()
+			delete(subscribers.listeners, id)
+			subscribers.mutex.Unlock()
+		})
+	}
+}
+
+// hand the stats to the listeners, the lock isn't held while they run so a
+// listener may unsubscribe itself
+func (this *subscriberList) publish(start time.Time, stats []ContainerStats) {
+	this.mutex.Lock()
+	ids := make([]int, 0, len(this.listeners))
+	for id := range this.listeners {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	listeners := make([]pollListener, 0, len(ids))
+	for _, id := range ids {
+		listeners = append(listeners, this.listeners[id])
+	}
+	this.mutex.Unlock()
+	for _, listener := range listeners {
+		listener(start, stats)
+	}
+}
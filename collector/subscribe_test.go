@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	host := newFakeHost(t)
+	id := fakeId("f")
+	host.addContainer(id)
+	c := newTestCollector(t, host)
+
+	var first, second [][]ContainerStats
+	unsubscribe := c.Subscribe(func(stats []ContainerStats) { first = append(first, stats) })
+	unsubscribeSecond := c.Subscribe(func(stats []ContainerStats) { second = append(second, stats) })
+	defer unsubscribeSecond()
+
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+	// the polls of the command line which print nothing are published too
+	savedSummary, savedOptions := pollSummary, options()
+	o := options()
+	o.exporter = nopExporter{}
+	setOptions(o)
+	pollSummary, baselinePoll = true, true
+	err := getCurrentStat(context.Background())
+	pollSummary, baselinePoll = savedSummary, false
+	setOptions(savedOptions)
+	if err != nil {
+		t.Fatalf("getCurrentStat: %s", err)
+	}
+	unsubscribe()
+	unsubscribe()
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+
+	if len(first) != 2 || len(second) != 3 {
+		t.Fatalf("the subscribers got %d and %d polls, want 2 before the unsubscribe and 3", len(first), len(second))
+	}
+	for i, stats := range second {
+		if len(stats) != 1 || stats[0].Id != id {
+			t.Errorf("poll %d published %+v, want the container", i, stats)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// the CFS throttling of the container since the previous sample, or the
+// cumulative counters with -no-delta
+type ThrottleInfo struct {
+	// the enforcement periods and the ones the container hit its quota in
+	Periods          uint64
+	ThrottledPeriods uint64
+	// nanoseconds the container was throttled for
+	ThrottledTime uint64
+	// ThrottledPeriods / Periods in percent, 0 without periods
+	Percent float64
+}
+
+func (this *Container) UpdateThrottling(stat cgroups.ThrottlingData) {
+	this.throttle = ThrottleInfo{}
+	if noDelta {
+		this.throttle = ThrottleInfo{Periods: stat.Periods, ThrottledPeriods: stat.ThrottledPeriods, ThrottledTime: stat.ThrottledTime}
+	} else {
+		if this.previous == nil {
+			return
+		}
+		previous := this.previous.CpuStats.ThrottlingData
+		this.throttle = ThrottleInfo{
+			Periods:          counterDelta(stat.Periods, previous.Periods),
+			ThrottledPeriods: counterDelta(stat.ThrottledPeriods, previous.ThrottledPeriods),
+			ThrottledTime:    counterDelta(stat.ThrottledTime, previous.ThrottledTime),
+		}
+	}
+	if this.throttle.Periods > 0 {
+		this.throttle.Percent = float64(this.throttle.ThrottledPeriods) / float64(this.throttle.Periods) * 100
+	}
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// the summed stats of a group of containers
+type Totals struct {
+	Containers int
+	// CPU time used during the last poll, in nanoseconds
+	CpuUsage    uint64
+	MemoryUsage uint64
+	Pids        uint64
+}
+
+func (this *Totals) Add(c *Container) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	this.Containers++
+	if c.current == nil {
+		return
+	}
+	// the usage is still cumulative until there is a baseline
+	if !c.WarmingUp() {
+		this.CpuUsage += c.current.CpuStats.CpuUsage.TotalUsage
+	}
+	this.MemoryUsage += c.current.MemoryStats.Usage.Usage
+	this.Pids += c.current.PidsStats.Current
+}
+
+func (this *Totals) Print(name string) {
+	fmt.Println(this.line(name))
+}
+
+func (this *Totals) line(name string) string {
+	return fmt.Sprintf("%s%s containers=%d cpu_usage(%s)=%g memory_usage=%d pids=%d", name, collectorLabel(), this.Containers, timeUnit,
+		scaleTime(float64(this.CpuUsage)), this.MemoryUsage, this.Pids)
+}
+
+// log one line per poll with the totals of all containers instead of a line
+// per container, set by -summary
+var pollSummary bool
+
+// when the previous poll started, to turn the CPU time into a percentage
+var lastPoll time.Time
+
+func (this *Totals) LogSummary(start time.Time, errors int) {
+	fields := log.Fields{
+		"containers":   this.Containers,
+		"memory_bytes": this.MemoryUsage,
+		"pids":         this.Pids,
+		"duration":     time.Since(start).String(),
+		"errors":       errors,
+	}
+	// the percentage needs the usage per poll
+	if !lastPoll.IsZero() && !noDelta {
+		fields["cpu_percent"] = float64(this.CpuUsage) / float64(start.Sub(lastPoll).Nanoseconds()) * 100
+	}
+	log.WithFields(fields).Info("poll done")
+}
@@ -0,0 +1,30 @@
+package collector
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the build, set with -ldflags like
+// -X $pkg.version=1.2.0 -X $pkg.commit=$(git rev-parse HEAD) -X $pkg.buildDate=$(date -u +%FT%TZ)
+// with pkg=github.com/konghui/docker-metrics/collector
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "docker_collector_build_info",
+	Help: "Always 1, the version, commit and build date of the collector.",
+}, []string{"version", "commit", "build_date", "goversion"})
+
+func init() {
+	buildInfo.WithLabelValues(version, commit, buildDate, runtime.Version()).Set(1)
+}
+
+func versionString() string {
+	return fmt.Sprintf("docker-metrics %s (commit %s, built %s, %s)", version, commit, buildDate, runtime.Version())
+}
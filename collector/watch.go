@@ -0,0 +1,165 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+
+	log "github.com/Sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// track the containers with inotify on the docker cgroup dirs instead of
+// listing them each poll, set by -watch. The stats are still sampled on the
+// timer.
+var watch bool
+
+// the container set kept up to date from the create and remove events
+type containerWatcher struct {
+	mutex sync.Mutex
+	ids   map[string]bool
+	fd    int
+	// the layout of each watched dir by watch descriptor
+	layouts map[int]cgroupLayout
+}
+
+// the subsystem whose docker dirs are watched, every container has a dir
+// in each of them
+func watchedMount(cpath map[string]string) (mount string, ok bool) {
+	for _, name := range []string{unifiedSubsystem, "memory", "cpu", "pids"} {
+		if mount, ok = cpath[name]; ok {
+			return
+		}
+	}
+	names := make([]string, 0, len(cpath))
+	for name := range cpath {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return cpath[names[0]], true
+}
+
+func startWatcher() (w *containerWatcher, err error) {
+	var cpath map[string]string
+	cpath, err = getCgroupsPath()
+	if err != nil {
+		return
+	}
+	mount, ok := watchedMount(cpath)
+	if !ok {
+		return nil, fmt.Errorf("no mounted cgroup subsystem to watch")
+	}
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init inotify: %w", err)
+	}
+	w = &containerWatcher{ids: make(map[string]bool), fd: fd, layouts: make(map[int]cgroupLayout)}
+	for _, l := range cgroupLayouts {
+		dir := path.Join(mount, l.parent)
+		wd, werr := unix.InotifyAddWatch(fd, dir, unix.IN_CREATE|unix.IN_DELETE|unix.IN_MOVED_FROM|unix.IN_MOVED_TO|unix.IN_ONLYDIR)
+		if werr != nil {
+			log.Debugf("failed to watch %s: %s", dir, werr.Error())
+			continue
+		}
+		w.layouts[wd] = l
+		log.Infof("watch %s for containers", dir)
+	}
+	if len(w.layouts) == 0 {
+		unix.Close(fd)
+		return nil, fmt.Errorf("none of the docker cgroup dirs below %s can be watched", mount)
+	}
+	// list after the watches are added, so no container starting in between is missed
+	if err = w.rescan(); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	go w.run()
+	return
+}
+
+// replace the set with the listed containers
+func (this *containerWatcher) rescan() error {
+	list, err := GetContainerList()
+	if err != nil {
+		return err
+	}
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.ids = make(map[string]bool, len(list))
+	for _, id := range list {
+		this.ids[id] = true
+	}
+	return nil
+}
+
+func (this *containerWatcher) run() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := unix.Read(this.fd, buf)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			log.Errorf("failed to read the inotify events, stop watching: %s", err.Error())
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameStart := off + unix.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[nameStart:nameStart+int(event.Len)], "\x00"))
+			off = nameStart + int(event.Len)
+			this.handle(event.Wd, event.Mask, name)
+		}
+	}
+}
+
+func (this *containerWatcher) handle(wd int32, mask uint32, name string) {
+	// the kernel dropped events, the set can't be trusted anymore
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		if err := this.rescan(); err != nil {
+			log.Warnf("failed to list the containers after an inotify overflow: %s", err.Error())
+		}
+		return
+	}
+	l, ok := this.layouts[int(wd)]
+	if !ok || mask&unix.IN_ISDIR == 0 || !strings.HasPrefix(name, l.prefix) || !strings.HasSuffix(name, l.suffix) {
+		return
+	}
+	id := containerIdRe.FindString(name)
+	if id == "" {
+		return
+	}
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	switch {
+	case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		this.ids[id] = true
+	case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+		delete(this.ids, id)
+	}
+}
+
+// the tracked containers sorted by id
+func (this *containerWatcher) List(ctx context.Context) ([]string, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	list := make([]string, 0, len(this.ids))
+	for id := range this.ids {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// the containers from the watcher with -watch, from the -discovery otherwise
+func listContainers(ctx context.Context) ([]string, error) {
+	return discoverer.List(ctx)
+}
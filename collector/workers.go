@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"runtime"
+	"time"
+)
+
+// the largest pool -workers 0 grows to
+const maxAutoWorkers = 64
+
+// the share of the -interval the collection of a poll may take before the
+// auto sized pool grows, and below which it shrinks again
+const (
+	workersGrowShare   = 0.5
+	workersShrinkShare = 0.1
+)
+
+// the pool size of -workers 0, adjusted after each poll from the time the
+// collection took. It starts and shrinks back to a worker per CPU.
+type workerPool struct {
+	size int
+}
+
+var autoWorkers = workerPool{size: runtime.NumCPU()}
+
+// the workers for a poll of the containers, -workers or the auto size, never
+// more than the containers
+func (this *workerPool) workers(containers int) (n int) {
+	n = workers
+	if n == 0 {
+		n = this.size
+	}
+	if n > containers {
+		n = containers
+	}
+	if n < 1 {
+		n = 1
+	}
+	return
+}
+
+// grow the pool when the collection took much of the interval, so the
+// containers' reads overlap more, and shrink it when it's idle
+func (this *workerPool) observe(took time.Duration, interval time.Duration) {
+	share := took.Seconds() / interval.Seconds()
+	switch {
+	case share > workersGrowShare && this.size < maxAutoWorkers:
+		this.size *= 2
+		if this.size > maxAutoWorkers {
+			this.size = maxAutoWorkers
+		}
+	case share < workersShrinkShare && this.size > runtime.NumCPU():
+		this.size /= 2
+		if this.size < runtime.NumCPU() {
+			this.size = runtime.NumCPU()
+		}
+	}
+}
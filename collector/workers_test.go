@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	saved := workers
+	defer func() { workers = saved }()
+	workers = 0
+	cpus := runtime.NumCPU()
+	pool := workerPool{size: cpus}
+
+	if n := pool.workers(1); n != 1 {
+		t.Errorf("%d workers for one container", n)
+	}
+	// the collection takes most of the interval, the pool doubles up to the cap
+	for i := 0; i < 10; i++ {
+		pool.observe(9*time.Second, 10*time.Second)
+	}
+	if pool.size != maxAutoWorkers && cpus < maxAutoWorkers {
+		t.Errorf("pool of %d after slow polls, want %d", pool.size, maxAutoWorkers)
+	}
+	if n := pool.workers(1000); n != pool.size {
+		t.Errorf("%d workers for 1000 containers, want the pool of %d", n, pool.size)
+	}
+	// a share in between keeps it
+	pool.observe(3*time.Second, 10*time.Second)
+	if pool.size != maxAutoWorkers && cpus < maxAutoWorkers {
+		t.Errorf("pool of %d after a poll of 30%%", pool.size)
+	}
+	// idle, back to a worker per CPU
+	for i := 0; i < 10; i++ {
+		pool.observe(10*time.Millisecond, 10*time.Second)
+	}
+	if pool.size != cpus && cpus < maxAutoWorkers {
+		t.Errorf("pool of %d after idle polls, want %d", pool.size, cpus)
+	}
+
+	// a fixed -workers
+	workers = 3
+	if n := pool.workers(1000); n != 3 {
+		t.Errorf("%d workers with -workers 3", n)
+	}
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// the extended attributes of the cgroup directory exposed as labels, set by
+// -xattr-labels as a comma separated list like user.pod,user.team
+var xattrLabels []string
+
+func parseList(s string) (list []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+// read the configured xattrs from the cgroup dirs, the first dir having a key wins
+func readXattrLabels(cpath map[string]string) (labels map[string]string) {
+	labels = make(map[string]string)
+	dirs := make([]string, 0, len(cpath))
+	for p := range coMounted(cpath) {
+		dirs = append(dirs, p)
+	}
+	sort.Strings(dirs)
+	buf := make([]byte, 4096)
+	for _, key := range xattrLabels {
+		for _, dir := range dirs {
+			n, err := unix.Getxattr(dir, key, buf)
+			if err != nil {
+				continue
+			}
+			// values set from the shell often end with a newline or a NUL
+			labels[key] = strings.TrimSpace(strings.TrimRight(string(buf[:n]), "\x00"))
+			break
+		}
+	}
+	return
+}